@@ -0,0 +1,160 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Archived notes stay in the board file with IsArchived set. They are left out
+// of the board and only listed in the archive browser.
+
+// Archived lists the archived notes, the latest archived first. Notes that
+// are also in the trash, on their own or with their section, are left to the
+// trash.
+func (b *BoardState) Archived() []*Note {
+	notes := []*Note{}
+	for _, n := range b.Notes {
+		_, onBoard := b.SectionByID(n.SectionID)
+		if n.IsArchived && !n.IsDeleted && onBoard {
+			notes = append(notes, n)
+		}
+	}
+	slices.SortStableFunc(notes, func(a, b *Note) int { return b.DateArchived.Compare(a.DateArchived) })
+	return notes
+}
+
+// AutoArchive archives the notes that have been checked for more than ArchiveAfter days
+func (m *ProgramModel) AutoArchive() {
+	if m.ArchiveAfter <= 0 {
+		return
+	}
+	before := time.Now().AddDate(0, 0, -m.ArchiveAfter)
+	due := 0
+	for _, section := range m.SectionData {
+		for _, n := range m.NotesIn(section.ID) {
+			if n.IsChecked && checkedAt(n).Before(before) {
+				due++
+			}
+		}
+	}
+	if due == 0 {
+		return
+	}
+	if m.applyOrReport(ArchiveOlderCmd{Before: before}) {
+		m.StatusText = fmt.Sprintf("Archived %s checked more than %d days ago", plural(due, "note"), m.ArchiveAfter)
+	}
+}
+
+// checkedAt is when n was checked. Notes checked before DateChecked existed
+// count from their last update.
+func checkedAt(n *Note) time.Time {
+	if n.DateChecked.IsZero() {
+		return n.DateUpdated
+	}
+	return n.DateChecked
+}
+
+// ArchiveChecked asks before archiving every checked note of the section under the cursor
+func (m ProgramModel) ArchiveChecked() (ProgramModel, tea.Cmd) {
+	section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
+	if !ok {
+		return m, nil
+	}
+	checked := 0
+	for _, n := range m.NotesIn(section.ID) {
+		if n.IsChecked {
+			checked++
+		}
+	}
+	if checked == 0 {
+		m.StatusText = "No checked notes in " + section.Name
+		return m, nil
+	}
+
+	id := section.ID
+	return m.Confirm(ConfirmBulk, ConfirmDialog{
+		Message:  fmt.Sprintf("Archive the %s checked in %s?", plural(checked, "note"), section.Name),
+		YesLabel: "archive",
+		Yes: func(m ProgramModel) (ProgramModel, tea.Cmd) {
+			if m.applyOrReport(ArchiveCheckedCmd{SectionID: id}) {
+				m.ClampCursor()
+			}
+			return m, nil
+		},
+	})
+}
+
+func (m *ProgramModel) OpenArchive() {
+	m.UIControl.Overlay = ArchiveOverlay
+	m.UIControl.ArchiveCursor = 0
+}
+
+func (m ProgramModel) UpdateArchive(msg tea.KeyMsg) (ProgramModel, tea.Cmd) {
+	notes := m.Archived()
+	cursor := &m.UIControl.ArchiveCursor
+
+	var note *Note
+	if *cursor < len(notes) {
+		note = notes[*cursor]
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m.Quit()
+
+	case "esc", "q", "ctrl+a":
+		m.UIControl.Overlay = NoOverlay
+
+	case "up", "k":
+		if *cursor > 0 {
+			*cursor--
+		}
+
+	case "down", "j":
+		if *cursor < len(notes)-1 {
+			*cursor++
+		}
+
+	case "r", "enter":
+		if note != nil {
+			m.applyOrReport(UnarchiveNoteCmd{ID: note.ID})
+		}
+
+	case "u":
+		if !m.Undo() {
+			m.StatusText = "Nothing to undo"
+		}
+	}
+
+	*cursor = clamp(0, *cursor, len(m.Archived())-1)
+	return m, nil
+}
+
+func (m ProgramModel) ArchiveView() string {
+	notes := m.Archived()
+	now := time.Now()
+	text := boardPickerTitleStyle.Render("Archive") + "\n\n"
+
+	if len(notes) == 0 {
+		text += "Nothing is archived yet, x archives the note under the cursor\n"
+	}
+	for i, note := range notes {
+		cursor := "  "
+		if i == m.UIControl.ArchiveCursor {
+			cursor = boardPickerCursorStyle.Render("> ")
+		}
+		section, ok := m.SectionByID(note.SectionID)
+		from := section.Name
+		if !ok {
+			from = "a deleted section"
+		}
+		text += fmt.Sprintf("%s%s  %s\n", cursor, note.Content,
+			scrollHintStyle.Render("from "+from+", archived "+relativeTime(note.DateArchived, now)))
+	}
+
+	text += "\nr: back to the board  u: undo  esc: close"
+	return boardPickerStyle.Render(text)
+}
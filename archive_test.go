@@ -0,0 +1,69 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestArchiveKeys(t *testing.T) {
+	tests := []struct {
+		name     string
+		keys     []string
+		want     []string // Titles left on the board
+		archived []string // Titles in the archive, the latest first
+	}{
+		{"archive a note", []string{"x"}, []string{"two", "three"}, []string{"one"}},
+		{"archive checked", []string{" ", "j", "j", " ", "X", "y"}, []string{"two"}, []string{"one", "three"}},
+		{"archive checked asks first", []string{" ", "X", "n"}, []string{"one", "two", "three"}, []string{}},
+		{"nothing checked", []string{"X"}, []string{"one", "two", "three"}, []string{}},
+		{"restore from the archive", []string{"x", "x", "ctrl+a", "j", "r", "esc"}, []string{"three", "one"}, []string{"two"}},
+		{"undo in the archive", []string{"x", "ctrl+a", "r", "u", "esc"}, []string{"two", "three"}, []string{"one"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := play(testModel(t), "a", "+one", "enter", "a", "+two", "enter", "a", "+three", "enter")
+			m.SelectNote(m.NoteAt(0, 0).ID)
+
+			m = play(m, tt.keys...)
+			if got := titles(m, 0); !slices.Equal(got, tt.want) {
+				t.Errorf("board has %q, want %q", got, tt.want)
+			}
+			archived := []string{}
+			for _, n := range m.Archived() {
+				archived = append(archived, n.Content)
+			}
+			if !slices.Equal(archived, tt.archived) {
+				t.Errorf("archive has %q, want %q", archived, tt.archived)
+			}
+		})
+	}
+}
+
+func TestAutoArchive(t *testing.T) {
+	m := testModel(t)
+	old := time.Now().AddDate(0, 0, -10)
+	for _, cmd := range []Command{
+		AddNoteCmd{SectionID: 0, Content: "old"},
+		AddNoteCmd{SectionID: 0, Content: "recent"},
+		AddNoteCmd{SectionID: 0, Content: "open"},
+	} {
+		m.Apply(cmd)
+	}
+	m.NoteAt(0, 0).IsChecked, m.NoteAt(0, 0).DateChecked = true, old
+	m.NoteAt(0, 1).IsChecked, m.NoteAt(0, 1).DateChecked = true, time.Now()
+	m.NoteAt(0, 2).DateUpdated = old
+
+	m.AutoArchive()
+	if len(m.Archived()) != 0 {
+		t.Fatal("archived with ArchiveAfter at 0")
+	}
+	m.ArchiveAfter = 7
+	m.AutoArchive()
+	if archived := m.Archived(); len(archived) != 1 || archived[0].Content != "old" {
+		t.Errorf("archived %v", archived)
+	}
+	if m.StatusText != "Archived 1 note checked more than 7 days ago" {
+		t.Errorf("status is %q", m.StatusText)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How long the board has to stay untouched before it is saved automatically
+const autoSaveDelay = 2 * time.Second
+
+type autoSaveMsg struct {
+	seq int // ChangeSeq at the time the timer was started
+}
+
+// ScheduleAutoSave starts the debounce timer for the change numbered seq
+func ScheduleAutoSave(seq int) tea.Cmd {
+	return tea.Tick(autoSaveDelay, func(time.Time) tea.Msg {
+		return autoSaveMsg{seq: seq}
+	})
+}
+
+// MarkDirty records that the board has changes that are not saved yet.
+// Call it after every successful mutation, once it went to the journal.
+func (m *ProgramModel) MarkDirty() {
+	m.ChangeSeq++
+	// A journal has the change on disk already, unless writing it failed
+	if j, ok := m.Store.(Journal); ok && !j.Pending() {
+		return
+	}
+	m.Dirty = true
+}
+
+// Save writes the board to its Store and clears the dirty flag
+func (m *ProgramModel) Save() error {
+	if err := m.Store.Save(m.BoardState); err != nil {
+		return err
+	}
+	m.Dirty = false
+	return nil
+}
+
+// Quit quits, asking to save first when there are unsaved changes
+func (m ProgramModel) Quit() (ProgramModel, tea.Cmd) {
+	if !m.Dirty {
+		return m, tea.Quit
+	}
+	return m.Confirm(ConfirmQuit, ConfirmDialog{
+		Message:  "You have unsaved changes on " + m.BoardName + ".",
+		YesLabel: "save and quit",
+		Yes: func(m ProgramModel) (ProgramModel, tea.Cmd) {
+			if err := m.Save(); err != nil {
+				m.StatusText = "Save failed: " + err.Error()
+				return m, nil
+			}
+			return m, tea.Quit
+		},
+		NoLabel: "quit without saving",
+		No: func(m ProgramModel) (ProgramModel, tea.Cmd) {
+			return m, tea.Quit
+		},
+	})
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestJournalChangesAreNotDirty(t *testing.T) {
+	dir := t.TempDir()
+	m, err := initialModel(NewBoardRegistry(dir, JournalFormat, 0), "board")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m = play(m, "a", "+one", "enter", " ", "u", "ctrl+r")
+	if m.Dirty {
+		t.Error("dirty after changes that are in the journal")
+	}
+
+	next, cmd := m.Update(keyMsg("q"))
+	if next.(ProgramModel).UIControl.IsDialogOpened {
+		t.Error("q asks about unsaved changes")
+	}
+	if cmd == nil {
+		t.Fatal("q didn't quit")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Error("q didn't quit")
+	}
+
+	state, err := m.Store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(state.Notes) != 1 || !state.Notes[0].IsChecked {
+		t.Errorf("journal has %d notes: %+v", len(state.Notes), state.Notes)
+	}
+}
+
+func TestFailedJournalAppendIsDirty(t *testing.T) {
+	dir := t.TempDir()
+	m, err := initialModel(NewBoardRegistry(dir, JournalFormat, 0), "board")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A directory where the journal should be makes every append fail
+	if err := os.Mkdir(filepath.Join(dir, "board"+journalExt), 0700); err != nil {
+		t.Fatal(err)
+	}
+	m = play(m, "a", "+one", "enter")
+	if !m.Dirty {
+		t.Error("not dirty after the journal couldn't be written")
+	}
+}
+
+// ctrl+c anywhere goes through Quit, so unsaved changes are never lost on the way out
+func TestCtrlCAsksAboutUnsavedChanges(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string // Opens an overlay or a dialog
+	}{
+		{"board", nil},
+		{"dialog", []string{"D"}},
+		{"trash", []string{"ctrl+t"}},
+		{"archive", []string{"ctrl+a"}},
+		{"tag picker", []string{"T"}},
+		{"board picker", []string{"b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := play(testModel(t), "a", "+one", "enter")
+			m = press(m, tt.keys...)
+
+			next, cmd := m.Update(keyMsg("ctrl+c"))
+			m = next.(ProgramModel)
+			if cmd != nil {
+				if _, ok := cmd().(tea.QuitMsg); ok {
+					t.Fatal("quit without asking")
+				}
+			}
+			if !m.UIControl.IsDialogOpened || m.UIControl.Dialog.YesLabel != "save and quit" {
+				t.Errorf("asked %q", m.UIControl.Dialog.Message)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultBackups     = 5
+	backupDirName      = "backups"
+	backupTimeLayout   = "20060102-150405"
+	backupMinimumSpace = 10 * time.Minute // Don't let auto-save push every backup out within minutes
+)
+
+// backupDir is where the old versions of the board files in dir are kept
+func backupDir(boardPath string) string {
+	return filepath.Join(filepath.Dir(boardPath), backupDirName)
+}
+
+// backupName turns board.json saved at t into board-20260102-150405.json
+func backupName(boardPath string, t time.Time) string {
+	ext := filepath.Ext(boardPath)
+	base := strings.TrimSuffix(filepath.Base(boardPath), ext)
+	return base + "-" + t.Format(backupTimeLayout) + ext
+}
+
+// listBackups returns the backups of boardPath, newest first
+func listBackups(boardPath string) ([]string, error) {
+	entries, err := os.ReadDir(backupDir(boardPath))
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(boardPath)
+	prefix := strings.TrimSuffix(filepath.Base(boardPath), ext) + "-"
+
+	backups := []string{}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, ext) {
+			continue
+		}
+		// "a-b-<time>" starts with "a-" too, only accept an exact timestamp
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ext)
+		if _, err := time.Parse(backupTimeLayout, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, filepath.Join(backupDir(boardPath), name))
+	}
+
+	// The timestamp layout sorts the same as time
+	slices.Sort(backups)
+	slices.Reverse(backups)
+	return backups, nil
+}
+
+func backupTime(backupPath string) time.Time {
+	name := strings.TrimSuffix(filepath.Base(backupPath), filepath.Ext(backupPath))
+	if len(name) < len(backupTimeLayout) {
+		return time.Time{}
+	}
+	t, _ := time.ParseInLocation(backupTimeLayout, name[len(name)-len(backupTimeLayout):], time.Local)
+	return t
+}
+
+// backup copies the current board file into the backup directory before it is
+// overwritten, then drops the oldest backups beyond s.Backups.
+func (s *JSONFileStore) backup() error {
+	if s.Backups <= 0 {
+		return nil
+	}
+
+	current, err := os.ReadFile(s.Path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	// Keeping a copy of a broken file would push out a good one
+	if !json.Valid(current) {
+		return nil
+	}
+
+	backups, err := listBackups(s.Path)
+	if err != nil {
+		return err
+	}
+	if len(backups) > 0 && time.Since(backupTime(backups[0])) < backupMinimumSpace {
+		return nil
+	}
+
+	if err := os.MkdirAll(backupDir(s.Path), 0700); err != nil {
+		return err
+	}
+	target := filepath.Join(backupDir(s.Path), backupName(s.Path, time.Now()))
+	if err := writeFileAtomic(target, current, 0600); err != nil {
+		return err
+	}
+	backups = append([]string{target}, backups...)
+
+	for _, old := range backups[min(s.Backups, len(backups)):] {
+		os.Remove(old)
+	}
+	return nil
+}
+
+// LatestValidBackup returns the newest backup that loads without error
+func (s *JSONFileStore) LatestValidBackup() (string, bool) {
+	backups, err := listBackups(s.Path)
+	if err != nil {
+		return "", false
+	}
+	for _, backup := range backups {
+		if _, err := NewJSONFileStore(backup).Load(); err == nil {
+			return backup, true
+		}
+	}
+	return "", false
+}
+
+// Restore puts backupPath back in place of the board file. The broken file is
+// kept next to it as <name>.corrupt-<time> in case it can be fixed by hand.
+func (s *JSONFileStore) Restore(backupPath string) error {
+	data, err := os.ReadFile(backupPath)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(s.Path); err == nil {
+		if err := os.Rename(s.Path, s.Path+".corrupt-"+time.Now().Format(backupTimeLayout)); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(s.Path, data, 0600)
+}
+
+// BackupRestorer is implemented by stores that can bring back an older copy of the board
+type BackupRestorer interface {
+	LatestValidBackup() (string, bool)
+	Restore(backupPath string) error
+}
+
+func (m ProgramModel) UpdateRecovery(msg tea.KeyMsg) (ProgramModel, tea.Cmd) {
+	switch msg.String() {
+	case "y", "enter":
+		restorer := m.Store.(BackupRestorer)
+		if err := restorer.Restore(m.UIControl.RecoveryBackup); err != nil {
+			m.StatusText = "Restore failed: " + err.Error()
+			return m, nil
+		}
+		state, err := m.Store.Load()
+		if err != nil {
+			m.StatusText = "Restore failed: " + err.Error()
+			return m, nil
+		}
+		m.BoardState = state
+		m.History = History{}
+		m.UIControl.Overlay = NoOverlay
+		m.ClampCursor()
+		m.StatusText = "Board restored from " + filepath.Base(m.UIControl.RecoveryBackup)
+
+	case "n", "q", "ctrl+c":
+		// Leave the broken file alone so nothing is lost
+		return m, tea.Quit
+	}
+	return m, nil
+}
+
+func (m ProgramModel) RecoveryView() string {
+	backup := m.UIControl.RecoveryBackup
+	return dialogStyle.Render(
+		"The board " + m.BoardName + " could not be read:\n" +
+			m.UIControl.RecoveryReason + "\n\n" +
+			"The newest good backup is from " + backupTime(backup).Format("2006-01-02 15:04:05") + ".\n\n" +
+			"y: restore it (the broken file is kept)  n: quit and leave everything as it is",
+	)
+}
@@ -0,0 +1,163 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// writeBackups puts a valid backup of boardPath in place for every time
+func writeBackups(t *testing.T, boardPath string, times ...time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(backupDir(boardPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, at := range times {
+		if err := os.WriteFile(filepath.Join(backupDir(boardPath), backupName(boardPath, at)), []byte(`{}`), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestListBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "board.json")
+	old := time.Date(2026, 1, 2, 15, 4, 5, 0, time.Local)
+	writeBackups(t, path, old, old.Add(time.Hour))
+	// Backups of other boards and files that only look like one
+	for _, name := range []string{"board-x-20260102-150405.json", "boards-20260102-150405.json", "board-20260102.json", "board-20260102-150405.txt"} {
+		os.WriteFile(filepath.Join(backupDir(path), name), []byte(`{}`), 0600)
+	}
+
+	backups, err := listBackups(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{backupName(path, old.Add(time.Hour)), backupName(path, old)}
+	if len(backups) != len(want) {
+		t.Fatalf("got %v, want %v", backups, want)
+	}
+	for i := range want {
+		if filepath.Base(backups[i]) != want[i] {
+			t.Errorf("backup %d is %s, want %s", i, backups[i], want[i])
+		}
+	}
+	if got := backupTime(backups[1]); !got.Equal(old) {
+		t.Errorf("backupTime is %s, want %s", got, old)
+	}
+}
+
+func TestSaveKeepsBackups(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name    string
+		current string      // The board file before the save, none when empty
+		backups []time.Time // Already there before the save
+		keep    int
+		want    int  // Backups after the save
+		added   bool // Whether the save added one
+	}{
+		{"first save", "", nil, 3, 0, false},
+		{"first backup", `{}`, nil, 3, 1, true},
+		{"oldest are dropped", `{}`, []time.Time{day(1), day(2), day(3)}, 3, 3, true},
+		{"no backups", `{}`, nil, 0, 0, false},
+		{"broken file isn't kept", `{"Notes": [`, []time.Time{day(1)}, 3, 1, false},
+		{"not again within minutes", `{}`, []time.Time{time.Now().Add(-time.Minute)}, 3, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "board.json")
+			if tt.current != "" {
+				os.WriteFile(path, []byte(tt.current), 0600)
+			}
+			writeBackups(t, path, tt.backups...)
+
+			store := NewJSONFileStore(path)
+			store.Backups = tt.keep
+			if err := store.Save(BoardState{}); err != nil {
+				t.Fatal(err)
+			}
+			backups, _ := listBackups(path)
+			if len(backups) != tt.want {
+				t.Fatalf("%d backups, want %d", len(backups), tt.want)
+			}
+			if added := len(backups) > 0 && time.Since(backupTime(backups[0])) < time.Minute; added != tt.added {
+				t.Errorf("added a backup %v, want %v", added, tt.added)
+			}
+			if len(tt.backups) == 3 && filepath.Base(backups[2]) != backupName(path, day(2)) {
+				t.Errorf("kept %v", backups)
+			}
+		})
+	}
+}
+
+func TestRecoverCorruptBoard(t *testing.T) {
+	dir := t.TempDir()
+	boards := NewBoardRegistry(dir, JSONFormat, defaultBackups)
+	path := boards.Path("board")
+	os.WriteFile(path, []byte(`{"Notes": [`), 0600)
+
+	if _, err := initialModel(boards, "board"); !errors.As(err, new(*CorruptBoardError)) {
+		t.Fatalf("got %v without a backup, want a corrupt board", err)
+	}
+
+	// A broken backup is passed over for an older good one
+	good := time.Now().Add(-2 * time.Hour)
+	writeBackups(t, path, good)
+	os.WriteFile(filepath.Join(backupDir(path), backupName(path, time.Now().Add(-time.Hour))), []byte(`[`), 0600)
+	os.WriteFile(filepath.Join(backupDir(path), backupName(path, good)), []byte(`{"SectionData": [{"ID": 0, "Name": "Saved"}]}`), 0600)
+
+	m, err := initialModel(boards, "board")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.UIControl.Overlay != RecoveryOverlay || filepath.Base(m.UIControl.RecoveryBackup) != backupName(path, good) {
+		t.Fatalf("offered %q", m.UIControl.RecoveryBackup)
+	}
+
+	m, _ = m.UpdateRecovery(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if m.UIControl.Overlay != NoOverlay || len(m.SectionData) != 1 || m.SectionData[0].Name != "Saved" {
+		t.Fatalf("restored %+v: %s", m.SectionData, m.StatusText)
+	}
+	if corrupt, _ := filepath.Glob(path + ".corrupt-*"); len(corrupt) != 1 {
+		t.Errorf("the broken file wasn't kept: %v", corrupt)
+	}
+	if _, err := NewJSONFileStore(path).Load(); err != nil {
+		t.Errorf("the board doesn't load after restoring: %v", err)
+	}
+}
+
+func TestRecoveryQuitLeavesFilesAlone(t *testing.T) {
+	for _, key := range []string{"n", "q"} {
+		dir := t.TempDir()
+		boards := NewBoardRegistry(dir, JSONFormat, defaultBackups)
+		path := boards.Path("board")
+		os.WriteFile(path, []byte(`{"Notes": [`), 0600)
+		writeBackups(t, path, time.Now().Add(-time.Hour))
+
+		m, err := initialModel(boards, "board")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, cmd := m.UpdateRecovery(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		if cmd == nil {
+			t.Fatalf("%s didn't quit", key)
+		}
+		if data, _ := os.ReadFile(path); string(data) != `{"Notes": [` {
+			t.Errorf("%s changed the board file to %q", key, data)
+		}
+	}
+}
+
+func TestNullBoardIsCorrupt(t *testing.T) {
+	boards := NewBoardRegistry(t.TempDir(), JSONFormat, defaultBackups)
+	os.WriteFile(boards.Path("board"), []byte("null\n"), 0600)
+
+	if _, err := initialModel(boards, "board"); !errors.As(err, new(*CorruptBoardError)) {
+		t.Errorf("got %v, want a corrupt board", err)
+	}
+}
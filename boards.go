@@ -0,0 +1,336 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	defaultBoardName = "board"
+	boardExt         = ".json"
+)
+
+// Formats a board can be stored in
+const (
+	JSONFormat    = "json"    // The whole board in one JSON file, see JSONFileStore
+	JournalFormat = "journal" // An append-only log of changes, see JournalStore
+)
+
+// BoardRegistry keeps every board as <name>.json (or <name>.jsonl for
+// journals) inside one directory
+type BoardRegistry struct {
+	Dir          string
+	Format       string // JSONFormat or JournalFormat
+	Backups      int    // Passed on to every JSONFileStore
+	CompactEvery int    // Passed on to every JournalStore
+}
+
+func NewBoardRegistry(dir string, format string, backups int) BoardRegistry {
+	return BoardRegistry{Dir: dir, Format: format, Backups: backups, CompactEvery: defaultCompactEvery}
+}
+
+func (r BoardRegistry) ext() string {
+	if r.Format == JournalFormat {
+		return journalExt
+	}
+	return boardExt
+}
+
+func (r BoardRegistry) Path(name string) string {
+	return filepath.Join(r.Dir, name+r.ext())
+}
+
+// Store opens the storage of a single board. Nothing is read until Load is called.
+func (r BoardRegistry) Store(name string) Store {
+	if r.Format == JournalFormat {
+		store := NewJournalStore(r.Path(name))
+		store.CompactEvery = r.CompactEvery
+		return store
+	}
+	store := NewJSONFileStore(r.Path(name))
+	store.Backups = r.Backups
+	return store
+}
+
+func (r BoardRegistry) Exists(name string) bool {
+	_, err := os.Stat(r.Path(name))
+	return err == nil
+}
+
+// List returns the names of all boards, sorted
+func (r BoardRegistry) List() ([]string, error) {
+	entries, err := os.ReadDir(r.Dir)
+	if os.IsNotExist(err) {
+		return []string{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), r.ext()) {
+			continue
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), r.ext()))
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+// Create saves a blank board under name
+func (r BoardRegistry) Create(name string) error {
+	if err := validateBoardName(name); err != nil {
+		return err
+	}
+	if r.Exists(name) {
+		return fmt.Errorf("board %q already exists", name)
+	}
+	return r.Store(name).Save(LoadBlankProgramState().BoardState)
+}
+
+func (r BoardRegistry) Rename(oldName, newName string) error {
+	if err := validateBoardName(newName); err != nil {
+		return err
+	}
+	if r.Exists(newName) {
+		return fmt.Errorf("board %q already exists", newName)
+	}
+	return os.Rename(r.Path(oldName), r.Path(newName))
+}
+
+func (r BoardRegistry) Delete(name string) error {
+	return os.Remove(r.Path(name))
+}
+
+func validateBoardName(name string) error {
+	switch {
+	case strings.TrimSpace(name) == "":
+		return errors.New("board name can't be empty")
+	case strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, "."):
+		return fmt.Errorf("%q is not a valid board name", name)
+	}
+	return nil
+}
+
+// openBoard loads the board from store. A board that was never saved starts blank.
+func openBoard(store Store) (BoardState, error) {
+	state, err := store.Load()
+	if os.IsNotExist(err) {
+		return LoadBlankProgramState().BoardState, nil
+	}
+	return state, err
+}
+
+// BoardPicker is the state of the board switcher overlay
+type BoardPicker struct {
+	Names  []string
+	Cursor int
+}
+
+func (m *ProgramModel) OpenBoardPicker() {
+	names, err := m.Boards.List()
+	if err != nil {
+		m.StatusText = "Could not list boards: " + err.Error()
+		return
+	}
+	// The current board might not be saved yet, list it anyway
+	if !slices.Contains(names, m.BoardName) {
+		names = append(names, m.BoardName)
+		slices.Sort(names)
+	}
+
+	m.UIControl.Overlay = BoardPickerOverlay
+	m.UIControl.BoardPicker = BoardPicker{
+		Names:  names,
+		Cursor: max(slices.Index(names, m.BoardName), 0),
+	}
+}
+
+func (p BoardPicker) Selected() (string, bool) {
+	if p.Cursor < 0 || p.Cursor >= len(p.Names) {
+		return "", false
+	}
+	return p.Names[p.Cursor], true
+}
+
+// SwitchBoard saves pending changes and replaces the board with the one called name
+func (m ProgramModel) SwitchBoard(name string) (ProgramModel, tea.Cmd) {
+	store := m.Boards.Store(name)
+	state, err := openBoard(store)
+	if err != nil {
+		m.StatusText = fmt.Sprintf("Could not open board %s: %v", name, err)
+		return m, nil
+	}
+
+	if m.Dirty {
+		if err := m.Save(); err != nil {
+			m.StatusText = "Save failed: " + err.Error()
+			return m, nil
+		}
+	}
+	m.Store.Close()
+
+	m.Store = store
+	m.StoreEvents, _ = store.Watch()
+	m.BoardName = name
+	m.BoardState = state
+	m.History = History{}
+	m.UIControl.TagFilter = nil
+	m.UIControl.Search = ""
+	m.UIControl.ViewName = ""
+	m.UIControl.SectionCursor = 0
+	m.UIControl.RowCursor = 0
+	m.RepopulateDisplayOrder()
+	m.AutoPurge()
+	m.AutoArchive()
+
+	return m, waitForStoreChange(m.StoreEvents)
+}
+
+func (m ProgramModel) UpdateBoardPicker(msg tea.KeyMsg) (ProgramModel, tea.Cmd) {
+	var cmd tea.Cmd
+	picker := &m.UIControl.BoardPicker
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m.Quit()
+
+	case "esc", "b", "q":
+		m.UIControl.Overlay = NoOverlay
+
+	case "up", "k":
+		if picker.Cursor > 0 {
+			picker.Cursor--
+		}
+
+	case "down", "j":
+		if picker.Cursor < len(picker.Names)-1 {
+			picker.Cursor++
+		}
+
+	case "enter":
+		name, ok := picker.Selected()
+		if !ok {
+			break
+		}
+		m.UIControl.Overlay = NoOverlay
+		if name != m.BoardName {
+			return m.SwitchBoard(name)
+		}
+
+	case "n":
+		m.Operation = NewBoardOperation
+		m.IsTextInputShown = true
+		m.InputPrompt = "What is the name of the new board?"
+		m.TextInput.Placeholder = "Type the board's name here"
+		m.TextInput.SetValue("")
+		m.TextInput, cmd = m.TextInput.Update(nil)
+		m.TextInput.Focus()
+
+	case "r":
+		name, ok := picker.Selected()
+		if !ok {
+			break
+		}
+		m.Operation = RenameBoardOperation
+		m.IsTextInputShown = true
+		m.InputPrompt = "What is the new name of this board?"
+		m.TextInput.Placeholder = "Type the board's name here"
+		m.TextInput.SetValue(name)
+		m.TextInput, cmd = m.TextInput.Update(nil)
+		m.TextInput.Focus()
+
+	case "d":
+		name, ok := picker.Selected()
+		if !ok {
+			break
+		}
+		if name == m.BoardName {
+			m.StatusText = "Can't delete the board you are on, switch to another one first"
+			break
+		}
+		return m.Confirm(ConfirmDeleteBoard, ConfirmDialog{
+			Message:  "Delete the board " + name + " and its file for good?",
+			YesLabel: "delete",
+			Yes: func(m ProgramModel) (ProgramModel, tea.Cmd) {
+				if err := m.Boards.Delete(name); err != nil {
+					m.StatusText = "Delete failed: " + err.Error()
+					return m, nil
+				}
+				m.OpenBoardPicker()
+				m.StatusText = "Board " + name + " deleted"
+				return m, nil
+			},
+		})
+	}
+
+	return m, cmd
+}
+
+// CreateBoard handles the NEWBOARD text input and switches to the new board
+func (m ProgramModel) CreateBoard(name string) (ProgramModel, tea.Cmd) {
+	name = strings.TrimSpace(name)
+	if err := m.Boards.Create(name); err != nil {
+		m.StatusText = err.Error()
+		return m, nil
+	}
+	m.UIControl.Overlay = NoOverlay
+	return m.SwitchBoard(name)
+}
+
+// RenameBoard handles the RENAMEBOARD text input for the board under the picker's cursor
+func (m ProgramModel) RenameBoard(newName string) (ProgramModel, tea.Cmd) {
+	oldName, ok := m.UIControl.BoardPicker.Selected()
+	newName = strings.TrimSpace(newName)
+	if !ok || oldName == newName {
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	if oldName == m.BoardName {
+		// Make sure the file exists before moving it, the board might be brand new
+		if err := m.Save(); err != nil {
+			m.StatusText = "Save failed: " + err.Error()
+			return m, nil
+		}
+	}
+	if err := m.Boards.Rename(oldName, newName); err != nil {
+		m.StatusText = err.Error()
+		return m, nil
+	}
+
+	if oldName == m.BoardName {
+		m.Store.Close()
+		m.Store = m.Boards.Store(newName)
+		m.StoreEvents, _ = m.Store.Watch()
+		m.BoardName = newName
+		cmd = waitForStoreChange(m.StoreEvents)
+	}
+
+	m.OpenBoardPicker()
+	return m, cmd
+}
+
+func (m ProgramModel) BoardPickerView() string {
+	text := boardPickerTitleStyle.Render("Boards") + "\n\n"
+
+	for i, name := range m.UIControl.BoardPicker.Names {
+		line := "  " + name
+		if name == m.BoardName {
+			line += " (current)"
+		}
+		if i == m.UIControl.BoardPicker.Cursor {
+			line = boardPickerCursorStyle.Render("> " + strings.TrimPrefix(line, "  "))
+		}
+		text += line + "\n"
+	}
+
+	text += "\nenter: switch  n: new  r: rename  d: delete  esc: close"
+	return boardPickerStyle.Render(text)
+}
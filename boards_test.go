@@ -0,0 +1,70 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+// boardsModel is on the board "work" with "board" saved next to it
+func boardsModel(t *testing.T) ProgramModel {
+	t.Helper()
+	m := testModel(t)
+	m = press(m, "a")
+	m = typeText(m, "hello")
+	m = press(m, "enter", "b", "n")
+	m = typeText(m, "work")
+	m = press(m, "enter")
+	if m.BoardName != "work" || !m.Boards.Exists("board") {
+		t.Fatalf("on %s, board exists %v: %s", m.BoardName, m.Boards.Exists("board"), m.StatusText)
+	}
+	return m
+}
+
+func TestBoardPickerDelete(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []string
+		exists bool
+	}{
+		{"y deletes", []string{"y"}, false},
+		{"enter deletes", []string{"enter"}, false},
+		{"n keeps", []string{"n"}, true},
+		{"esc keeps", []string{"esc"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := press(boardsModel(t), "b", "k", "d")
+			if !m.UIControl.IsDialogOpened {
+				t.Fatal("d didn't ask first")
+			}
+			m = press(m, tt.keys...)
+
+			if m.UIControl.IsDialogOpened {
+				t.Error("the dialog is still open")
+			}
+			if m.Boards.Exists("board") != tt.exists {
+				t.Errorf("board exists %v, want %v", m.Boards.Exists("board"), tt.exists)
+			}
+			if m.BoardName != "work" || !m.Boards.Exists("work") {
+				t.Errorf("on %s, work exists %v", m.BoardName, m.Boards.Exists("work"))
+			}
+			if m.UIControl.Overlay != BoardPickerOverlay {
+				t.Error("the picker closed")
+			}
+			if got := slices.Contains(m.UIControl.BoardPicker.Names, "board"); got != tt.exists {
+				t.Errorf("picker lists board %v, want %v", got, tt.exists)
+			}
+		})
+	}
+}
+
+func TestBoardPickerKeepsOpenBoard(t *testing.T) {
+	m := press(boardsModel(t), "b", "d")
+	if m.UIControl.IsDialogOpened {
+		t.Fatal("asked to delete the open board")
+	}
+	m = press(m, "y")
+	if !m.Boards.Exists("work") {
+		t.Fatal("the open board was deleted")
+	}
+}
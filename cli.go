@@ -0,0 +1,312 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Subcommands change a board without starting the UI, for shell scripts, git
+// hooks and cron jobs. They go through the same commands and stores as the UI:
+//
+//	kagoban add --section Inbox "Write the docs"
+//	kagoban ls tag:docs
+//	kagoban move 12 --to Done
+//	kagoban check 12
+//	kagoban rm 12
+//	kagoban export --format csv --output board.csv
+//	kagoban import backlog.md
+
+// subcommand runs against the board picked by the global flags. args are what
+// follows the subcommand's name.
+type subcommand struct {
+	usage string
+	run   func(board *headlessBoard, args []string, out io.Writer) error
+}
+
+var subcommands = map[string]subcommand{
+	"add":     {"add [--section NAME] [--tags TAGS] [--due DATE] TEXT", runAdd},
+	"ls":      {"ls [QUERY]", runList},
+	"move":    {"move ID --to SECTION", runMove},
+	"check":   {"check ID", runCheck},
+	"uncheck": {"uncheck ID", runUncheck},
+	"rm":      {"rm ID", runRemove},
+	"export":  {"export [--format md|csv|json|todo.txt] [--output FILE] [QUERY]", runExport},
+	"import":  {"import [--format FORMAT] [--replace] FILE", runImport},
+}
+
+// usageError is a mistake on the command line rather than on the board
+type usageError struct{ msg string }
+
+func (e usageError) Error() string { return e.msg }
+
+// runSubcommand runs args[0] with the rest of args and returns the exit code
+func runSubcommand(cfg Config, args []string, out, errOut io.Writer) int {
+	name := args[0]
+	sub, ok := subcommands[name]
+	if !ok {
+		fmt.Fprintf(errOut, "unknown command %q, use one of add, ls, move, check, uncheck, rm, export or import\n", name)
+		return 2
+	}
+
+	boards := NewBoardRegistry(cfg.BoardDir, cfg.Format, cfg.Backups)
+	boards.CompactEvery = cfg.CompactEvery
+	board, err := openHeadless(cfg.BoardName, boards.Store(cfg.BoardName))
+	if err != nil {
+		fmt.Fprintf(errOut, "Could not open board %s: %v\n", cfg.BoardName, err)
+		return 1
+	}
+	defer board.Store.Close()
+
+	err = sub.run(board, args[1:], out)
+	var usage usageError
+	switch {
+	case errors.Is(err, flag.ErrHelp):
+		fmt.Fprintf(out, "usage: kagoban %s\n", sub.usage)
+		return 0
+	case errors.As(err, &usage):
+		fmt.Fprintf(errOut, "%v\nusage: kagoban %s\n", err, sub.usage)
+		return 2
+	case err != nil:
+		fmt.Fprintln(errOut, err)
+		return 1
+	}
+	return 0
+}
+
+// headlessBoard is a board opened without the UI. Apply works like
+// ProgramModel.Apply without the undo history.
+type headlessBoard struct {
+	BoardState
+	Name  string
+	Store Store
+}
+
+func openHeadless(name string, store Store) (*headlessBoard, error) {
+	state, err := openBoard(store)
+	if err != nil {
+		return nil, err
+	}
+	return &headlessBoard{BoardState: state, Name: name, Store: store}, nil
+}
+
+// Apply runs cmds in order and writes the board back once they all went
+// through. When one fails nothing is written.
+func (h *headlessBoard) Apply(cmds ...Command) error {
+	at := time.Now()
+	for _, cmd := range cmds {
+		if err := h.BoardState.Apply(cmd, at); err != nil {
+			return err
+		}
+	}
+	if j, ok := h.Store.(Journal); ok {
+		// A failed append makes Save write a snapshot instead
+		for _, cmd := range cmds {
+			j.Append(cmd, at)
+		}
+	}
+	return h.Store.Save(h.BoardState)
+}
+
+// Section finds a section by name, ignoring case, or by ID
+func (h *headlessBoard) Section(name string) (*Section, error) {
+	for i := range h.SectionData {
+		if strings.EqualFold(h.SectionData[i].Name, name) {
+			return &h.SectionData[i], nil
+		}
+	}
+	if id, err := strconv.Atoi(name); err == nil {
+		if section, ok := h.SectionByID(id); ok {
+			return section, nil
+		}
+	}
+	return nil, fmt.Errorf("no section called %q", name)
+}
+
+// Note finds the note with the ID in arg, as long as it's on the board
+func (h *headlessBoard) Note(arg string) (*Note, error) {
+	id, err := strconv.Atoi(arg)
+	if err != nil {
+		return nil, usageError{fmt.Sprintf("%q is not a note ID, kagoban ls lists them", arg)}
+	}
+	note := h.NoteByID(id)
+	switch {
+	case note == nil:
+		return nil, fmt.Errorf("no note with ID %d", id)
+	case note.IsDeleted:
+		return nil, fmt.Errorf("note %d is in the trash", id)
+	case note.IsArchived:
+		return nil, fmt.Errorf("note %d is archived", id)
+	case !h.IsActive(note):
+		return nil, fmt.Errorf("note %d is in the trash with its section", id)
+	}
+	return note, nil
+}
+
+// parseArgs parses fs and returns exactly want positional arguments, or any
+// number of them when want is -1
+func parseArgs(fs *flag.FlagSet, args []string, want int) ([]string, error) {
+	fs.SetOutput(io.Discard)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, usageError{err.Error()}
+	}
+	if want >= 0 && fs.NArg() != want {
+		return nil, usageError{fmt.Sprintf("expected %s, got %d", plural(want, "argument"), fs.NArg())}
+	}
+	return fs.Args(), nil
+}
+
+func runAdd(board *headlessBoard, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("add", flag.ContinueOnError)
+	sectionName := fs.String("section", "", "section to add the note to, the first one when empty")
+	tags := fs.String("tags", "", "tags separated by spaces or commas")
+	due := fs.String("due", "", "due date, e.g. tomorrow, fri or 2026-11-01")
+	rest, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	content := strings.TrimSpace(rest[0])
+	if content == "" {
+		return usageError{"a note needs some text"}
+	}
+
+	section, ok := FindSectionDataByOrder(board.SectionData, 0)
+	if *sectionName != "" {
+		if section, err = board.Section(*sectionName); err != nil {
+			return err
+		}
+	} else if !ok {
+		return errors.New("the board has no sections")
+	}
+	var dueDate time.Time
+	if *due != "" {
+		if dueDate, err = parseDue(*due, time.Now()); err != nil {
+			return usageError{err.Error()}
+		}
+	}
+
+	// The note goes to the bottom of the section, where the tags and the due date find it
+	order := len(board.NotesIn(section.ID))
+	cmds := []Command{AddNoteCmd{SectionID: section.ID, Content: content}}
+	if t := parseTags(*tags); len(t) > 0 {
+		cmds = append(cmds, TagNoteCmd{SectionID: section.ID, Order: order, Tags: t})
+	}
+	if !dueDate.IsZero() {
+		cmds = append(cmds, DueNoteCmd{SectionID: section.ID, Order: order, DueDate: dueDate})
+	}
+	if err := board.Apply(cmds...); err != nil {
+		return err
+	}
+	note := board.NoteAt(section.ID, order)
+	fmt.Fprintln(out, note.ID)
+	return nil
+}
+
+func runList(board *headlessBoard, args []string, out io.Writer) error {
+	now := time.Now()
+	query, err := ParseQuery(strings.Join(args, " "), now)
+	if err != nil {
+		return usageError{err.Error()}
+	}
+
+	for i := range board.SectionData {
+		section, _ := FindSectionDataByOrder(board.SectionData, i)
+		notes := []*Note{}
+		for _, n := range board.NotesIn(section.ID) {
+			if query.Matches(&board.BoardState, n) {
+				notes = append(notes, n)
+			}
+		}
+		if len(notes) == 0 && query.Text != "" {
+			continue
+		}
+
+		fmt.Fprintln(out, section.Name)
+		for _, n := range notes {
+			check := "[ ]"
+			if n.IsChecked {
+				check = "[x]"
+			}
+			line := fmt.Sprintf("%4d %s %s", n.ID, check, n.Content)
+			for _, tag := range n.Tags {
+				line += " #" + tag
+			}
+			if !n.DueDate.IsZero() {
+				line += " (" + dueBadge(n.DueDate, now) + ")"
+			}
+			fmt.Fprintln(out, line)
+		}
+	}
+	return nil
+}
+
+func runMove(board *headlessBoard, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("move", flag.ContinueOnError)
+	to := fs.String("to", "", "section to move the note to")
+	// Let the ID come first, the flag package stops at the first argument
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		args = append(slices.Clone(args[1:]), args[0])
+	}
+	rest, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *to == "" {
+		return usageError{"--to is required"}
+	}
+
+	note, err := board.Note(rest[0])
+	if err != nil {
+		return err
+	}
+	section, err := board.Section(*to)
+	if err != nil {
+		return err
+	}
+	return board.Apply(MoveNoteCmd{SectionID: note.SectionID, Order: note.Order, ToSectionID: section.ID, ToOrder: -1})
+}
+
+func runCheck(board *headlessBoard, args []string, out io.Writer) error {
+	return setChecked(board, args, true)
+}
+
+func runUncheck(board *headlessBoard, args []string, out io.Writer) error {
+	return setChecked(board, args, false)
+}
+
+// setChecked toggles the note only when it isn't checked already, so running
+// check twice is harmless
+func setChecked(board *headlessBoard, args []string, checked bool) error {
+	rest, err := parseArgs(flag.NewFlagSet("check", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	note, err := board.Note(rest[0])
+	if err != nil {
+		return err
+	}
+	if note.IsChecked == checked {
+		return nil
+	}
+	return board.Apply(ToggleNoteCmd{SectionID: note.SectionID, Order: note.Order})
+}
+
+func runRemove(board *headlessBoard, args []string, out io.Writer) error {
+	rest, err := parseArgs(flag.NewFlagSet("rm", flag.ContinueOnError), args, 1)
+	if err != nil {
+		return err
+	}
+	note, err := board.Note(rest[0])
+	if err != nil {
+		return err
+	}
+	return board.Apply(DeleteNoteCmd{SectionID: note.SectionID, Order: note.Order})
+}
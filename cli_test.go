@@ -0,0 +1,102 @@
+package main
+
+import (
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// runCLI runs a subcommand against the board of m and opens the board again
+func runCLI(t *testing.T, m ProgramModel, args ...string) ProgramModel {
+	t.Helper()
+	cfg := Config{BoardDir: m.Boards.Dir, BoardName: m.BoardName, Format: m.Boards.Format}
+	var errOut strings.Builder
+	if code := runSubcommand(cfg, args, io.Discard, &errOut); code != 0 {
+		t.Fatalf("%v exited with %d: %s", args, code, errOut.String())
+	}
+	m, err := initialModel(m.Boards, m.BoardName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.RepopulateDisplayOrder()
+	return m
+}
+
+func TestCLILongNotesSurviveEditing(t *testing.T) {
+	title := "Investigate why the nightly backup job fails on the staging cluster"
+	tags := []string{"good-first-issue", "help-wanted", "enhancement", "backend"}
+	m := runCLI(t, testModel(t), "add", "--tags", strings.Join(tags, " "), title)
+
+	m = press(m, "e", "enter", "t", "enter")
+	note := m.SelectedNote()
+	if note == nil {
+		t.Fatal("no note under the cursor")
+	}
+	if note.Content != title {
+		t.Errorf("title is %q", note.Content)
+	}
+	if !slices.Equal(note.Tags, tags) {
+		t.Errorf("tags are %v", note.Tags)
+	}
+}
+
+func TestCLILeavesTrashedSectionsAlone(t *testing.T) {
+	m := testModel(t)
+	id := m.NewID()
+	for _, cmd := range []Command{
+		AddSectionCmd{ID: id, Name: "Later"},
+		AddNoteCmd{SectionID: id, Content: "someday"},
+		DeleteSectionCmd{SectionID: id},
+	} {
+		if err := m.Apply(cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	note := m.Notes[0].ID
+	cfg := Config{BoardDir: m.Boards.Dir, BoardName: m.BoardName, Format: m.Boards.Format}
+
+	for _, args := range [][]string{{"check", strconv.Itoa(note)}, {"rm", strconv.Itoa(note)}, {"move", strconv.Itoa(note), "--to", "Inbox"}} {
+		var errOut strings.Builder
+		if code := runSubcommand(cfg, args, io.Discard, &errOut); code != 1 || !strings.Contains(errOut.String(), "in the trash") {
+			t.Errorf("%v exited with %d: %s", args, code, errOut.String())
+		}
+	}
+	var out strings.Builder
+	runSubcommand(cfg, []string{"ls"}, &out, io.Discard)
+	if strings.Contains(out.String(), "someday") {
+		t.Errorf("ls lists a note of a trashed section:\n%s", out.String())
+	}
+}
+
+func TestCLIAddWithTagsAndDue(t *testing.T) {
+	for _, format := range []string{JSONFormat, JournalFormat} {
+		t.Run(format, func(t *testing.T) {
+			cfg := Config{BoardDir: t.TempDir(), BoardName: "board", Format: format}
+			var out, errOut strings.Builder
+			runSubcommand(cfg, []string{"add", "first"}, io.Discard, io.Discard)
+			if code := runSubcommand(cfg, []string{"add", "--tags", "docs", "--due", "2026-11-01", "second"}, &out, &errOut); code != 0 {
+				t.Fatalf("exited with %d: %s", code, errOut.String())
+			}
+
+			boards := NewBoardRegistry(cfg.BoardDir, format, 0)
+			state, err := boards.Store("board").Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			id, _ := strconv.Atoi(strings.TrimSpace(out.String()))
+			note := state.NoteByID(id)
+			if note == nil || note.Content != "second" || note.Order != 1 {
+				t.Fatalf("printed %q for %+v", out.String(), note)
+			}
+			if !slices.Equal(note.Tags, []string{"docs"}) || note.DueDate.Format(time.DateOnly) != "2026-11-01" {
+				t.Errorf("tags %v and due %s", note.Tags, note.DueDate)
+			}
+		})
+	}
+}
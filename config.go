@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Config holds everything that can be set from the command line or environment
+type Config struct {
+	BoardDir  string // Directory holding every board, see BoardRegistry
+	BoardName string // Board to open on start
+	Format    string // JSONFormat or JournalFormat
+	Backups   int    // How many old versions of each board file to keep
+
+	CompactEvery int // Journal events between snapshots, 0 keeps every event
+	PurgeAfter   int // Days deleted notes and sections stay in the trash, 0 keeps them
+
+	NoConfirm    []ConfirmAction // Actions that don't ask before they run
+	ArchiveAfter int             // Days a note stays checked before it is archived, 0 never
+
+	Args []string // A subcommand and its arguments, see cli.go. Empty starts the UI.
+}
+
+// LoadConfig reads flags from args, falling back to the environment and then
+// to the XDG defaults. Flags win over environment variables.
+func LoadConfig(args []string) (Config, error) {
+	fs := flag.NewFlagSet("kagoban", flag.ContinueOnError)
+	board := fs.String("board", "", "name of the board to open, or path of a board file (env KAGOBAN_BOARD)")
+	format := fs.String("format", JSONFormat, `how boards are stored: "json" rewrites the board file on save, "journal" appends every change to <board>.jsonl`)
+	backups := fs.Int("backups", defaultBackups, "how many backups of the board file to keep, 0 turns them off")
+	compactEvery := fs.Int("compact-every", defaultCompactEvery, "compact a journal into a snapshot after this many changes, 0 keeps the full history")
+	purgeAfter := fs.Int("purge-after", 0, "delete notes and sections for good once they were in the trash this many days, 0 keeps them")
+	archiveAfter := fs.Int("auto-archive", 0, "archive notes that have been checked for more than this many days, 0 never archives on its own")
+	noConfirm := fs.String("no-confirm", "", `comma separated actions that run without asking first: delete-section, delete-board, replace-board, purge, bulk, quit or "all". Quitting without asking saves first`)
+	if err := fs.Parse(args); err != nil {
+		return Config{}, err
+	}
+	skipped, err := parseConfirmActions(*noConfirm)
+	if err != nil {
+		return Config{}, err
+	}
+
+	value := *board
+	if value == "" {
+		value = os.Getenv("KAGOBAN_BOARD")
+	}
+
+	if *format != JSONFormat && *format != JournalFormat {
+		return Config{}, fmt.Errorf("unknown format %q, use %q or %q", *format, JSONFormat, JournalFormat)
+	}
+
+	cfg := Config{
+		BoardDir:     dataDir(),
+		BoardName:    defaultBoardName,
+		Format:       *format,
+		Backups:      *backups,
+		CompactEvery: *compactEvery,
+		PurgeAfter:   *purgeAfter,
+		NoConfirm:    skipped,
+		ArchiveAfter: *archiveAfter,
+		Args:         fs.Args(),
+	}
+	switch {
+	case value == "":
+	case strings.HasSuffix(value, journalExt):
+		// The extension tells a journal apart from a board file
+		cfg.Format = JournalFormat
+		cfg.BoardDir = filepath.Dir(value)
+		cfg.BoardName = strings.TrimSuffix(filepath.Base(value), journalExt)
+	case strings.ContainsAny(value, `/\`) || strings.HasSuffix(value, boardExt):
+		// A path to a board file, the boards next to it make up the registry
+		if ext := filepath.Ext(value); ext != "" && ext != boardExt {
+			return Config{}, fmt.Errorf("%s: board files end in %s, or %s for a journal", value, boardExt, journalExt)
+		}
+		cfg.BoardDir = filepath.Dir(value)
+		cfg.BoardName = strings.TrimSuffix(filepath.Base(value), boardExt)
+	default:
+		if err := validateBoardName(value); err != nil {
+			return Config{}, err
+		}
+		cfg.BoardName = value
+	}
+
+	return cfg, nil
+}
+
+// dataDir follows the XDG base directory spec: $XDG_DATA_HOME/kagoban,
+// or ~/.local/share/kagoban when it's not set.
+func dataDir() string {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "kagoban")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		// Nowhere better to go, keep the board next to the binary's working directory
+		return "data"
+	}
+	return filepath.Join(home, ".local", "share", "kagoban")
+}
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigBoardPath(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		board   string
+		dir     string
+		name    string
+		format  string
+		wantErr string
+	}{
+		{filepath.Join(dir, "work.json"), dir, "work", JSONFormat, ""},
+		{filepath.Join(dir, "work.jsonl"), dir, "work", JournalFormat, ""},
+		{filepath.Join(dir, "work"), dir, "work", JSONFormat, ""},
+		{"work.json", ".", "work", JSONFormat, ""},
+		{filepath.Join(dir, "todo.txt"), "", "", "", "board files end in .json"},
+		{filepath.Join(dir, "work.json.bak"), "", "", "", "board files end in .json"},
+	}
+	for _, tt := range tests {
+		t.Run(filepath.Base(tt.board), func(t *testing.T) {
+			cfg, err := LoadConfig([]string{"--board", tt.board})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %v, want an error saying %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cfg.BoardDir != tt.dir || cfg.BoardName != tt.name || cfg.Format != tt.format {
+				t.Errorf("got %s %s %s, want %s %s %s", cfg.BoardDir, cfg.BoardName, cfg.Format, tt.dir, tt.name, tt.format)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ConfirmAction names a kind of action that asks before it runs. The names
+// are what --no-confirm takes.
+type ConfirmAction string
+
+const (
+	ConfirmDeleteSection ConfirmAction = "delete-section"
+	ConfirmDeleteBoard   ConfirmAction = "delete-board"
+	ConfirmReplaceBoard  ConfirmAction = "replace-board" // Ctrl+g puts mock data over the board
+	ConfirmPurge         ConfirmAction = "purge"         // Deleting from the trash for good
+	ConfirmBulk          ConfirmAction = "bulk"          // Changes to many notes at once
+	ConfirmQuit          ConfirmAction = "quit"          // Quitting with unsaved changes
+)
+
+var confirmActions = []ConfirmAction{ConfirmDeleteSection, ConfirmDeleteBoard, ConfirmReplaceBoard, ConfirmPurge, ConfirmBulk, ConfirmQuit}
+
+// parseConfirmActions reads the value of --no-confirm, e.g. "purge,quit" or "all"
+func parseConfirmActions(s string) ([]ConfirmAction, error) {
+	actions := []ConfirmAction{}
+	for _, name := range strings.Split(s, ",") {
+		name = strings.TrimSpace(name)
+		switch {
+		case name == "":
+		case name == "all":
+			actions = append(actions, confirmActions...)
+		case slices.Contains(confirmActions, ConfirmAction(name)):
+			actions = append(actions, ConfirmAction(name))
+		default:
+			names := []string{}
+			for _, a := range confirmActions {
+				names = append(names, string(a))
+			}
+			return nil, fmt.Errorf("unknown action %q for --no-confirm, use all or some of %s", name, strings.Join(names, ", "))
+		}
+	}
+	return actions, nil
+}
+
+// dialogAction is what a dialog runs once answered
+type dialogAction func(m ProgramModel) (ProgramModel, tea.Cmd)
+
+// ConfirmDialog is a yes/no question shown on top of everything else while
+// UIControl.IsDialogOpened is set
+type ConfirmDialog struct {
+	Message  string
+	YesLabel string
+	Yes      dialogAction
+	// No runs on n when set, with a NoLabel for the footer. Otherwise n cancels like esc.
+	NoLabel string
+	No      dialogAction
+}
+
+// Confirm asks the question of dialog before running dialog.Yes, unless the
+// user turned confirmations off for action
+func (m ProgramModel) Confirm(action ConfirmAction, dialog ConfirmDialog) (ProgramModel, tea.Cmd) {
+	if slices.Contains(m.NoConfirm, action) {
+		return dialog.Yes(m)
+	}
+	if dialog.YesLabel == "" {
+		dialog.YesLabel = "yes"
+	}
+	m.UIControl.IsDialogOpened = true
+	m.UIControl.Dialog = dialog
+	return m, nil
+}
+
+func (m ProgramModel) UpdateDialog(msg tea.KeyMsg) (ProgramModel, tea.Cmd) {
+	dialog := m.UIControl.Dialog
+
+	switch msg.String() {
+	case "y", "enter":
+		m.closeDialog()
+		return dialog.Yes(m)
+
+	case "n":
+		m.closeDialog()
+		if dialog.No != nil {
+			return dialog.No(m)
+		}
+
+	case "esc":
+		m.closeDialog()
+
+	case "ctrl+c":
+		m.closeDialog()
+		return m.Quit()
+	}
+	return m, nil
+}
+
+func (m *ProgramModel) closeDialog() {
+	m.UIControl.IsDialogOpened = false
+	m.UIControl.Dialog = ConfirmDialog{}
+}
+
+func (m ProgramModel) DialogView() string {
+	dialog := m.UIControl.Dialog
+	keys := "y: " + dialog.YesLabel
+	if dialog.No != nil {
+		keys += "  n: " + dialog.NoLabel + "  esc: cancel"
+	} else {
+		keys += "  n/esc: cancel"
+	}
+	return dialogStyle.Render(dialog.Message + "\n\n" + keys)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// How close a due date has to be for the card to show up as due soon
+const dueSoonDays = 2
+
+// DueStatus is how urgent a note's due date is
+type DueStatus int
+
+const (
+	NotDue DueStatus = iota // No due date or already checked
+	DueLater
+	DueSoon
+	Overdue
+)
+
+// Formats parseDue understands besides the words, tried in order
+var dueDateLayouts = []string{"2006-01-02", "2006/01/02", "2 Jan 2006", "2 January 2006", "Jan 2 2006", "2 Jan", "2 January", "Jan 2", "January 2"}
+
+// parseDue reads a due date the way people say it: "today", "tomorrow",
+// "fri", "next fri", "in 3 days", "2w", "2026-11-01" or "1 nov". An empty
+// string, "none" or "clear" removes the due date and gives the zero time.
+// Dates are midnight local time, a note is overdue from the day after.
+func parseDue(s string, now time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	today := startOfDay(now)
+
+	switch s {
+	case "", "none", "clear", "-":
+		return time.Time{}, nil
+	case "today", "tod":
+		return today, nil
+	case "tomorrow", "tmr", "tom":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case "next week":
+		return today.AddDate(0, 0, 7), nil
+	case "next month":
+		return today.AddDate(0, 1, 0), nil
+	}
+
+	// "fri" is the coming Friday, today included. "next fri" is the one after.
+	day, next := strings.CutPrefix(s, "next ")
+	if weekday, ok := parseWeekday(day); ok {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		if next {
+			days += 7
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+
+	// "in 3 days", "3d", "2 weeks", "1m"
+	if n, unit, ok := parseOffset(strings.TrimPrefix(s, "in ")); ok {
+		if t, ok := addOffset(today, n, unit); ok {
+			return t, nil
+		}
+	}
+
+	for _, layout := range dueDateLayouts {
+		t, err := time.ParseInLocation(layout, s, now.Location())
+		if err != nil {
+			// Month names are parsed case sensitive, "1 nov" needs to be "1 Nov"
+			t, err = time.ParseInLocation(layout, titleWords(s), now.Location())
+		}
+		if err != nil {
+			continue
+		}
+		if !strings.Contains(layout, "2006") {
+			return nextDate(t.Month(), t.Day(), today), nil
+		}
+		return t, nil
+	}
+
+	return time.Time{}, fmt.Errorf("can't understand %q as a date, try tomorrow, fri or 2026-11-01", s)
+}
+
+// nextDate is the next time month and day come around, today included. Feb 29
+// waits for the next leap year.
+func nextDate(month time.Month, day int, today time.Time) time.Time {
+	for year := today.Year(); ; year++ {
+		t := time.Date(year, month, day, 0, 0, 0, 0, today.Location())
+		// time.Date rolls Feb 29 over to Mar 1 in other years
+		if t.Month() == month && t.Day() == day && !t.Before(today) {
+			return t
+		}
+	}
+}
+
+func parseWeekday(s string) (time.Weekday, bool) {
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		name := strings.ToLower(d.String())
+		if s == name || s == name[:3] {
+			return d, true
+		}
+	}
+	return 0, false
+}
+
+// parseOffset splits "3 days" or "3d" into 3 and "days"
+func parseOffset(s string) (int, string, bool) {
+	digits := strings.TrimRightFunc(s, func(r rune) bool { return r < '0' || r > '9' })
+	if digits == "" {
+		return 0, "", false
+	}
+	n, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, "", false
+	}
+	return n, strings.TrimSpace(s[len(digits):]), true
+}
+
+// addOffset adds n days, weeks or months to t
+func addOffset(t time.Time, n int, unit string) (time.Time, bool) {
+	switch unit {
+	case "d", "day", "days":
+		return t.AddDate(0, 0, n), true
+	case "w", "week", "weeks":
+		return t.AddDate(0, 0, 7*n), true
+	case "m", "month", "months":
+		return t.AddDate(0, n, 0), true
+	}
+	return t, false
+}
+
+func titleWords(s string) string {
+	words := strings.Fields(s)
+	for i, w := range words {
+		words[i] = strings.ToUpper(w[:1]) + w[1:]
+	}
+	return strings.Join(words, " ")
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// daysUntil counts calendar days from now to t, negative when t is past
+func daysUntil(t time.Time, now time.Time) int {
+	// Midnight UTC of both days, local midnights can be 23 or 25 hours apart
+	day := func(t time.Time) time.Time {
+		y, m, d := t.Date()
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	return int(day(t).Sub(day(now)).Hours() / 24)
+}
+
+func (n *Note) DueStatus(now time.Time) DueStatus {
+	if n.DueDate.IsZero() || n.IsChecked {
+		return NotDue
+	}
+	switch days := daysUntil(n.DueDate, now); {
+	case days < 0:
+		return Overdue
+	case days < dueSoonDays:
+		return DueSoon
+	}
+	return DueLater
+}
+
+// dueBadge is the short due date shown on a card, e.g. "due tomorrow" or "3d overdue"
+func dueBadge(due time.Time, now time.Time) string {
+	if due.IsZero() {
+		return ""
+	}
+	days := daysUntil(due, now)
+	switch {
+	case days < 0:
+		return fmt.Sprintf("%dd overdue", -days)
+	case days == 0:
+		return "due today"
+	case days == 1:
+		return "due tomorrow"
+	case days < 7:
+		return "due " + strings.ToLower(due.Weekday().String()[:3])
+	case due.Year() == now.Year():
+		return "due " + due.Format("2 Jan")
+	}
+	return "due " + due.Format("2 Jan 2006")
+}
+
+// cardFooter is the last line of a card: the due badge followed by the tags
+func cardFooter(note *Note, width int, now time.Time) string {
+	badge := ""
+	if !note.DueDate.IsZero() {
+		badge = dueBadgeStyle.Render(dueBadge(note.DueDate, now))
+	}
+	if len(note.Tags) == 0 {
+		return badge
+	}
+	if badge == "" {
+		return tagChips(note.Tags, width)
+	}
+	return badge + " " + tagChips(note.Tags, width-lg.Width(badge)-1)
+}
+
+// OpenDueEditor asks for the due date of the note under the cursor
+func (m *ProgramModel) OpenDueEditor() tea.Cmd {
+	note := m.SelectedNote()
+	if note == nil {
+		return nil
+	}
+	value := ""
+	if !note.DueDate.IsZero() {
+		value = note.DueDate.Format("2006-01-02")
+	}
+	return m.OpenTextInput(DueNoteOperation, "When is this note due? (tomorrow, fri, 2026-11-01, none)", "e.g. next fri", value)
+}
+
+// sortByDue orders notes by due date, the ones without one last. Notes due
+// the same day keep the order they had.
+func sortByDue(notes []*Note) {
+	slices.SortStableFunc(notes, func(a, b *Note) int {
+		switch {
+		case a.DueDate.IsZero() && b.DueDate.IsZero():
+			return 0
+		case a.DueDate.IsZero():
+			return 1
+		case b.DueDate.IsZero():
+			return -1
+		}
+		return startOfDay(a.DueDate).Compare(startOfDay(b.DueDate))
+	})
+}
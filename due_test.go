@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	now := time.Date(2026, 10, 18, 15, 30, 0, 0, time.Local) // A Sunday
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		in      string
+		want    time.Time
+		wantErr bool
+	}{
+		{"", time.Time{}, false},
+		{"none", time.Time{}, false},
+		{"today", day(2026, 10, 18), false},
+		{"Tomorrow", day(2026, 10, 19), false},
+		{"sun", day(2026, 10, 18), false},
+		{"fri", day(2026, 10, 23), false},
+		{"next fri", day(2026, 10, 30), false},
+		{"in 3 days", day(2026, 10, 21), false},
+		{"2w", day(2026, 11, 1), false},
+		{"2026-11-01", day(2026, 11, 1), false},
+		{"1 nov", day(2026, 11, 1), false},
+		{"Oct 18", day(2026, 10, 18), false},
+		{"oct 1", day(2027, 10, 1), false},
+		{"feb 29", day(2028, 2, 29), false},
+		{"29 feb", day(2028, 2, 29), false},
+		{"feb 30", time.Time{}, true},
+		{"someday", time.Time{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := parseDue(tt.in, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error %v, want error %v", err, tt.wantErr)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
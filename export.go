@@ -0,0 +1,249 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Exports are for reading elsewhere (standups, reports, spreadsheets), not
+// for loading back. Only the notes on the board are exported, the trash and
+// the archive are left out.
+
+const pathCharLimit = 255
+
+// exporters write an export in each format, by the name --format takes
+var exporters = map[string]func(w io.Writer, e exportedBoard) error{
+	"md":   exportMarkdown,
+	"csv":  exportCSV,
+	"json": exportJSON,
+
+	todoTxtFormat: exportTodoTxt,
+}
+
+// exportedBoard is the JSON export and what the other formats are rendered from
+type exportedBoard struct {
+	Board      string            `json:"board"`
+	ExportedAt time.Time         `json:"exportedAt"`
+	Sections   []exportedSection `json:"sections"`
+}
+
+type exportedSection struct {
+	ID    int            `json:"id"`
+	Name  string         `json:"name"`
+	Notes []exportedNote `json:"notes"`
+}
+
+type exportedNote struct {
+	ID          int       `json:"id"`
+	Order       int       `json:"order"`
+	Content     string    `json:"content"`
+	Description string    `json:"description,omitempty"`
+	Tags        []string  `json:"tags,omitempty"`
+	Checked     bool      `json:"checked"`
+	Due         time.Time `json:"due,omitzero"`
+	Created     time.Time `json:"created,omitzero"`
+	Updated     time.Time `json:"updated,omitzero"`
+	CheckedAt   time.Time `json:"checkedAt,omitzero"`
+}
+
+// newExport collects the sections in order with the notes keep lets through
+func newExport(name string, b *BoardState, keep func(n *Note) bool, now time.Time) exportedBoard {
+	e := exportedBoard{Board: name, ExportedAt: now, Sections: []exportedSection{}}
+	for i := range b.SectionData {
+		section, _ := FindSectionDataByOrder(b.SectionData, i)
+		es := exportedSection{ID: section.ID, Name: section.Name, Notes: []exportedNote{}}
+		for _, n := range b.NotesIn(section.ID) {
+			if !keep(n) {
+				continue
+			}
+			es.Notes = append(es.Notes, exportedNote{
+				ID:          n.ID,
+				Order:       n.Order,
+				Content:     n.Content,
+				Description: n.Description,
+				Tags:        n.Tags,
+				Checked:     n.IsChecked,
+				Due:         n.DueDate,
+				Created:     n.DateCreated,
+				Updated:     n.DateUpdated,
+				CheckedAt:   n.DateChecked,
+			})
+		}
+		e.Sections = append(e.Sections, es)
+	}
+	return e
+}
+
+func (e exportedBoard) noteCount() int {
+	count := 0
+	for _, s := range e.Sections {
+		count += len(s.Notes)
+	}
+	return count
+}
+
+// exportMarkdown writes a checklist per section, descriptions indented under their note
+func exportMarkdown(w io.Writer, e exportedBoard) error {
+	text := "# " + e.Board + "\n"
+	for _, s := range e.Sections {
+		text += "\n## " + s.Name + "\n\n"
+		if len(s.Notes) == 0 {
+			text += "_Nothing here_\n"
+		}
+		for _, n := range s.Notes {
+			check := " "
+			if n.Checked {
+				check = "x"
+			}
+			text += fmt.Sprintf("- [%s] %s", check, n.Content)
+			for _, tag := range n.Tags {
+				text += " #" + tag
+			}
+			if !n.Due.IsZero() {
+				text += " (due " + n.Due.Format(time.DateOnly) + ")"
+			}
+			text += "\n"
+			if n.Description != "" {
+				for _, line := range strings.Split(n.Description, "\n") {
+					text += "  " + line + "\n"
+				}
+			}
+		}
+	}
+	_, err := io.WriteString(w, text)
+	return err
+}
+
+// exportCSV writes one row per note, dates in RFC 3339 and empty when not set
+func exportCSV(w io.Writer, e exportedBoard) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"section", "order", "id", "content", "description", "tags", "checked", "due", "created", "updated", "checked_at"})
+	for _, s := range e.Sections {
+		for _, n := range s.Notes {
+			cw.Write([]string{
+				s.Name,
+				strconv.Itoa(n.Order),
+				strconv.Itoa(n.ID),
+				n.Content,
+				n.Description,
+				strings.Join(n.Tags, " "),
+				strconv.FormatBool(n.Checked),
+				csvTime(n.Due),
+				csvTime(n.Created),
+				csvTime(n.Updated),
+				csvTime(n.CheckedAt),
+			})
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func csvTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+func exportJSON(w io.Writer, e exportedBoard) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(e)
+}
+
+// exportFormat picks the format from the extension of path
+func exportFormat(path string) (string, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	switch ext {
+	case "markdown":
+		ext = "md"
+	case "txt":
+		ext = todoTxtFormat
+	}
+	if _, ok := exporters[ext]; !ok {
+		return "", fmt.Errorf("can't tell the format of %s, end it with .md, .csv, .json or .txt", path)
+	}
+	return ext, nil
+}
+
+// writeExport writes the export to the file at path
+func writeExport(path string, format string, e exportedBoard) error {
+	var text strings.Builder
+	if err := exporters[format](&text, e); err != nil {
+		return err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0700); err != nil {
+			return err
+		}
+	}
+	return writeFileAtomic(path, []byte(text.String()), 0600)
+}
+
+// OpenExport asks where to export the notes on screen, next to the board file by default
+func (m *ProgramModel) OpenExport() tea.Cmd {
+	path := filepath.Join(m.Boards.Dir, m.BoardName+".md")
+	return m.OpenTextInputLimit(ExportOperation, "Export the notes on screen to which file? (.md, .csv, .json or todo.txt)", "Type a file path here", path, pathCharLimit)
+}
+
+// Export writes the notes that pass the tag filter and the active view to path
+func (m *ProgramModel) Export(path string) {
+	path = strings.TrimSpace(path)
+	if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(path, "~/") {
+		path = filepath.Join(home, path[2:])
+	}
+	format, err := exportFormat(path)
+	if err != nil {
+		m.StatusText = err.Error()
+		return
+	}
+	e := newExport(m.BoardName, &m.BoardState, m.noteFilter(), time.Now())
+	if err := writeExport(path, format, e); err != nil {
+		m.StatusText = "Export failed: " + err.Error()
+		return
+	}
+	m.StatusText = fmt.Sprintf("Exported %s to %s", plural(e.noteCount(), "note"), path)
+}
+
+func runExport(board *headlessBoard, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	format := fs.String("format", "md", "md, csv, json or todo.txt")
+	output := fs.String("output", "", "file to write to instead of stdout, the format follows its extension unless --format is given")
+	rest, err := parseArgs(fs, args, -1)
+	if err != nil {
+		return err
+	}
+	formatSet := false
+	fs.Visit(func(f *flag.Flag) { formatSet = formatSet || f.Name == "format" })
+	if *output != "" && !formatSet {
+		if *format, err = exportFormat(*output); err != nil {
+			return usageError{err.Error()}
+		}
+	}
+	if _, ok := exporters[*format]; !ok {
+		return usageError{fmt.Sprintf("unknown format %q, use md, csv, json or todo.txt", *format)}
+	}
+
+	now := time.Now()
+	query, err := ParseQuery(strings.Join(rest, " "), now)
+	if err != nil {
+		return usageError{err.Error()}
+	}
+	e := newExport(board.Name, &board.BoardState, func(n *Note) bool { return query.Matches(&board.BoardState, n) }, now)
+
+	if *output == "" {
+		return exporters[*format](out, e)
+	}
+	return writeExport(*output, *format, e)
+}
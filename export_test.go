@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// exportBoard has a note with everything set, a checked one and one in the trash
+func exportBoard(t *testing.T) BoardState {
+	t.Helper()
+	b := testBoard()
+	for _, cmd := range []Command{
+		AddNoteCmd{SectionID: inbox, Content: "Write docs"},
+		DescribeNoteCmd{SectionID: inbox, Order: 0, Description: "first\nsecond"},
+		TagNoteCmd{SectionID: inbox, Order: 0, Tags: []string{"docs"}},
+		DueNoteCmd{SectionID: inbox, Order: 0, DueDate: time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)},
+		AddNoteCmd{SectionID: inbox, Content: "Ship, finally"},
+		ToggleNoteCmd{SectionID: inbox, Order: 1},
+		AddNoteCmd{SectionID: doing, Content: "gone"},
+		DeleteNoteCmd{SectionID: doing, Order: 0},
+	} {
+		if err := b.Apply(cmd, testNow); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return b
+}
+
+func TestExportMarkdown(t *testing.T) {
+	b := exportBoard(t)
+	var out strings.Builder
+	if err := exportMarkdown(&out, newExport("board", &b, func(*Note) bool { return true }, testNow)); err != nil {
+		t.Fatal(err)
+	}
+	want := "# board\n\n## Inbox\n\n- [ ] Write docs #docs (due 2026-11-01)\n  first\n  second\n- [x] Ship, finally\n\n## Doing\n\n_Nothing here_\n"
+	if out.String() != want {
+		t.Errorf("got\n%s\nwant\n%s", out.String(), want)
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	b := exportBoard(t)
+	var out strings.Builder
+	if err := exportCSV(&out, newExport("board", &b, func(*Note) bool { return true }, testNow)); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("%d rows, want a header and 2 notes", len(rows))
+	}
+	if got := strings.Join(rows[1][:7], "|"); got != "Inbox|0|2|Write docs|first\nsecond|docs|false" {
+		t.Errorf("first note is %q", got)
+	}
+	if rows[2][3] != "Ship, finally" || rows[2][6] != "true" || rows[2][7] != "" || rows[2][10] != testNow.Format(time.RFC3339) {
+		t.Errorf("second note is %q", rows[2])
+	}
+}
+
+func TestExportJSON(t *testing.T) {
+	b := exportBoard(t)
+	var out strings.Builder
+	if err := exportJSON(&out, newExport("board", &b, func(*Note) bool { return true }, testNow)); err != nil {
+		t.Fatal(err)
+	}
+	var got exportedBoard
+	if err := json.Unmarshal([]byte(out.String()), &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.Board != "board" || len(got.Sections) != 2 || got.noteCount() != 2 {
+		t.Fatalf("got %+v", got)
+	}
+	if note := got.Sections[0].Notes[0]; note.Description != "first\nsecond" || !note.Due.Equal(time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)) {
+		t.Errorf("first note is %+v", note)
+	}
+}
+
+func TestExportFormat(t *testing.T) {
+	tests := []struct {
+		path, want string
+	}{
+		{"board.md", "md"},
+		{"notes/Board.MARKDOWN", "md"},
+		{"board.csv", "csv"},
+		{"board.json", "json"},
+		{"board.txt", todoTxtFormat},
+		{"board.pdf", ""},
+		{"board", ""},
+	}
+	for _, tt := range tests {
+		got, err := exportFormat(tt.path)
+		if got != tt.want || (err != nil) != (tt.want == "") {
+			t.Errorf("exportFormat(%q) is %q, %v, want %q", tt.path, got, err, tt.want)
+		}
+	}
+}
+
+func TestRunExport(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{BoardDir: dir, BoardName: "board", Format: JSONFormat}
+	run := func(args ...string) string {
+		t.Helper()
+		var out, errOut strings.Builder
+		if code := runSubcommand(cfg, args, &out, &errOut); code != 0 {
+			t.Fatalf("%v exited with %d: %s", args, code, errOut.String())
+		}
+		return out.String()
+	}
+	run("add", "--tags", "docs", "one")
+	run("add", "two")
+
+	if got := run("export", "tag:docs"); !strings.Contains(got, "- [ ] one") || strings.Contains(got, "two") {
+		t.Errorf("exported\n%s", got)
+	}
+
+	// The format follows the extension of --output unless --format is given
+	path := filepath.Join(dir, "out", "board.csv")
+	run("export", "--output", path)
+	if data, err := os.ReadFile(path); err != nil || !strings.HasPrefix(string(data), "section,order,id,") {
+		t.Errorf("wrote %q, %v", data, err)
+	}
+	run("export", "--format", "json", "--output", path)
+	if data, _ := os.ReadFile(path); !strings.HasPrefix(string(data), "{") {
+		t.Errorf("wrote %q", data)
+	}
+
+	if code := runSubcommand(cfg, []string{"export", "--format", "pdf"}, io.Discard, io.Discard); code != 2 {
+		t.Errorf("unknown format exited with %d", code)
+	}
+}
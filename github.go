@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// githubItem is an item of a GitHub project as written by
+// gh project item-list <number> --owner <owner> --format json
+type githubItem struct {
+	Title   string   `json:"title"`
+	Status  string   `json:"status"`
+	Labels  []string `json:"labels"`
+	Content struct {
+		Body string `json:"body"`
+		URL  string `json:"url"`
+	} `json:"content"`
+}
+
+// githubNoStatus is the column GitHub shows items without a status in
+const githubNoStatus = "No Status"
+
+// parseGitHubProject maps the Status field to sections, in the order the
+// statuses first come up, and items to notes. Items in Done are checked.
+// Labels become tags and a date field with "due" or "target" in its name
+// becomes the due date.
+func parseGitHubProject(r io.Reader) (ImportedBoard, error) {
+	var export struct {
+		Items []json.RawMessage `json:"items"`
+	}
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return ImportedBoard{}, err
+	}
+	if export.Items == nil {
+		return ImportedBoard{}, errors.New("no items found, is this the output of gh project item-list --format json?")
+	}
+
+	board := ImportedBoard{}
+	for _, raw := range export.Items {
+		var item githubItem
+		if err := json.Unmarshal(raw, &item); err != nil {
+			return ImportedBoard{}, err
+		}
+		if strings.TrimSpace(item.Title) == "" {
+			continue
+		}
+		status := strings.TrimSpace(item.Status)
+		if status == "" {
+			status = githubNoStatus
+		}
+
+		note := ImportedNote{
+			Content:     strings.TrimSpace(item.Title),
+			Description: strings.TrimSpace(item.Content.Body),
+			Checked:     strings.EqualFold(status, "done"),
+			Due:         githubDue(raw),
+		}
+		if url := item.Content.URL; url != "" {
+			if note.Description != "" {
+				note.Description += "\n\n"
+			}
+			note.Description += url
+		}
+		labels := []string{}
+		for _, label := range item.Labels {
+			labels = append(labels, strings.ReplaceAll(strings.TrimSpace(label), " ", "-"))
+		}
+		note.Tags = parseTags(strings.Join(labels, " "))
+
+		idx := slices.IndexFunc(board.Sections, func(s ImportedSection) bool { return s.Name == status })
+		if idx == -1 {
+			board.Sections = append(board.Sections, ImportedSection{Name: status})
+			idx = len(board.Sections) - 1
+		}
+		board.Sections[idx].Notes = append(board.Sections[idx].Notes, note)
+	}
+	return board, nil
+}
+
+// githubDue looks through the custom fields of an item for a due date. gh
+// writes every field of the project next to the built in ones, named after it.
+func githubDue(raw json.RawMessage) time.Time {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return time.Time{}
+	}
+	for _, name := range slices.Sorted(maps.Keys(fields)) {
+		value := fields[name]
+		name = strings.ToLower(name)
+		if !strings.Contains(name, "due") && !strings.Contains(name, "target") {
+			continue
+		}
+		var s string
+		if json.Unmarshal(value, &s) != nil {
+			continue
+		}
+		if due, err := time.ParseInLocation(time.DateOnly, s, time.Local); err == nil {
+			return due
+		}
+	}
+	return time.Time{}
+}
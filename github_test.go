@@ -0,0 +1,46 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseGitHubProject(t *testing.T) {
+	day := func(m time.Month, d int) time.Time { return time.Date(2026, m, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name    string
+		input   string
+		want    ImportedBoard
+		wantErr bool
+	}{
+		{
+			"project",
+			`{"items": [
+				{"title": "First", "status": "Todo", "labels": ["bug", "help wanted"],
+					"content": {"body": "Some text", "url": "https://github.com/owner/repo/issues/1"}, "Due date": "2026-10-20"},
+				{"title": "Second", "status": "Done", "content": {}},
+				{"title": "Third", "content": {"url": "https://github.com/owner/repo/issues/3"}, "due": "soon", "target date": "2026-11-01"},
+				{"title": " ", "status": "Todo"},
+				{"title": "Fourth", "status": "Todo", "content": {"body": " "}}
+			], "totalCount": 5}`,
+			ImportedBoard{Sections: []ImportedSection{
+				{Name: "Todo", Notes: []ImportedNote{
+					{Content: "First", Description: "Some text\n\nhttps://github.com/owner/repo/issues/1", Tags: []string{"bug", "help-wanted"}, Due: day(10, 20)},
+					{Content: "Fourth", Tags: []string{}},
+				}},
+				{Name: "Done", Notes: []ImportedNote{{Content: "Second", Tags: []string{}, Checked: true}}},
+				{Name: githubNoStatus, Notes: []ImportedNote{{Content: "Third", Description: "https://github.com/owner/repo/issues/3", Tags: []string{}, Due: day(11, 1)}}},
+			}},
+			false,
+		},
+		{"no items", `{"items": []}`, ImportedBoard{}, false},
+		{"not gh output", `{"lists": []}`, ImportedBoard{}, true},
+		{"not JSON", `items`, ImportedBoard{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkImport(t, parseGitHubProject, tt.input, tt.want, tt.wantErr)
+		})
+	}
+}
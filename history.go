@@ -0,0 +1,81 @@
+package main
+
+import "time"
+
+// How many steps back undo can go
+const historyLimit = 100
+
+// Snapshot is everything undo needs to put back: the board and where the cursor was
+type Snapshot struct {
+	Board         BoardState
+	SectionCursor int
+	RowCursor     int
+}
+
+// UndoCmd and RedoCmd only go into journals. They mark where the user undid
+// or redid the last change, replaying them steps back through the boards from
+// before each change instead of storing the whole board again.
+type UndoCmd struct{}
+type RedoCmd struct{}
+
+func (UndoCmd) Kind() string { return "Undo" }
+func (RedoCmd) Kind() string { return "Redo" }
+
+// History holds the undo and redo stacks, newest last
+type History struct {
+	Undo []Snapshot
+	Redo []Snapshot
+}
+
+func (m ProgramModel) Snapshot() Snapshot {
+	return Snapshot{
+		Board:         CloneBoardState(m.BoardState),
+		SectionCursor: m.UIControl.SectionCursor,
+		RowCursor:     m.UIControl.RowCursor,
+	}
+}
+
+func (m *ProgramModel) restore(s Snapshot) {
+	m.BoardState = s.Board
+	m.UIControl.SectionCursor = s.SectionCursor
+	m.UIControl.RowCursor = s.RowCursor
+	m.ClampCursor()
+}
+
+// RecordChange is called after a mutation with the snapshot taken before it.
+// It makes the change undoable and marks the board dirty.
+func (m *ProgramModel) RecordChange(before Snapshot) {
+	m.History.Undo = append(m.History.Undo, before)
+	if len(m.History.Undo) > historyLimit {
+		m.History.Undo = m.History.Undo[len(m.History.Undo)-historyLimit:]
+	}
+	// A new change starts a new branch, the old future is gone
+	m.History.Redo = nil
+	m.MarkDirty()
+}
+
+func (m *ProgramModel) Undo() bool {
+	if len(m.History.Undo) == 0 {
+		return false
+	}
+	last := len(m.History.Undo) - 1
+	m.History.Redo = append(m.History.Redo, m.Snapshot())
+	m.restore(m.History.Undo[last])
+	m.History.Undo = m.History.Undo[:last]
+	m.journal(UndoCmd{}, time.Now())
+	m.MarkDirty()
+	return true
+}
+
+func (m *ProgramModel) Redo() bool {
+	if len(m.History.Redo) == 0 {
+		return false
+	}
+	last := len(m.History.Redo) - 1
+	m.History.Undo = append(m.History.Undo, m.Snapshot())
+	m.restore(m.History.Redo[last])
+	m.History.Redo = m.History.Redo[:last]
+	m.journal(RedoCmd{}, time.Now())
+	m.MarkDirty()
+	return true
+}
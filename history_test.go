@@ -0,0 +1,59 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUndoRedo(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []string
+		want   []string
+		status string
+	}{
+		{"undo add", []string{"a", "+one", "enter", "a", "+two", "enter", "u"}, []string{"one"}, ""},
+		{"undo twice", []string{"a", "+one", "enter", "a", "+two", "enter", "u", "u"}, []string{}, ""},
+		{"nothing to undo", []string{"a", "+one", "enter", "u", "u"}, []string{}, "Nothing to undo"},
+		{"redo", []string{"a", "+one", "enter", "a", "+two", "enter", "u", "u", "ctrl+r"}, []string{"one"}, ""},
+		{"nothing to redo", []string{"a", "+one", "enter", "ctrl+r"}, []string{"one"}, "Nothing to redo"},
+		{"undo edit", []string{"a", "+one", "enter", "e", "+s", "enter", "u"}, []string{"one"}, ""},
+		{"redo edit", []string{"a", "+one", "enter", "e", "+s", "enter", "u", "ctrl+r"}, []string{"ones"}, ""},
+		{"undo delete", []string{"a", "+one", "enter", "a", "+two", "enter", "d", "u"}, []string{"one", "two"}, ""},
+		{"a new change drops redo", []string{"a", "+one", "enter", "u", "a", "+two", "enter", "ctrl+r"}, []string{"two"}, "Nothing to redo"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := play(testModel(t), tt.keys...)
+			if got := titles(m, 0); !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+			if m.StatusText != tt.status && tt.status != "" {
+				t.Errorf("status is %q, want %q", m.StatusText, tt.status)
+			}
+			if !m.Dirty {
+				t.Error("not dirty after changes")
+			}
+		})
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	m := testModel(t)
+	for range historyLimit + 5 {
+		m.RecordChange(m.Snapshot())
+	}
+	if len(m.History.Undo) != historyLimit {
+		t.Errorf("%d steps to undo, want %d", len(m.History.Undo), historyLimit)
+	}
+}
+
+// Changing the board after a snapshot leaves the snapshot as it was
+func TestSnapshotIsACopy(t *testing.T) {
+	m := play(testModel(t), "a", "+one", "enter")
+	before := m.Snapshot()
+	m = play(m, "e", "+s", "enter", " ")
+	if note := before.Board.Notes[0]; note.Content != "one" || note.IsChecked {
+		t.Errorf("the snapshot changed to %+v", *note)
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+// Notes and sections get their IDs from one counter per board, BoardState.NextID.
+// It is saved with the board so IDs are never handed out twice, even across
+// launches. Note ID 0 means the note has no ID yet.
+
+// NewID hands out the next unused ID of the board
+func (b *BoardState) NewID() int {
+	id := max(b.NextID, 1)
+	b.NextID = id + 1
+	return id
+}
+
+// useID makes sure the counter never hands out id again
+func (b *BoardState) useID(id int) {
+	b.NextID = max(b.NextID, id+1)
+}
+
+// RepairIDs gives every note and section a unique ID and moves NextID past
+// all of them. Boards saved by older versions have every note at ID 0 and
+// sections from a counter that restarted on every launch. Returns whether
+// anything had to change.
+func (b *BoardState) RepairIDs() bool {
+	changed := false
+	for _, s := range b.SectionData {
+		b.useID(s.ID)
+	}
+	for _, s := range b.DeletedSections {
+		b.useID(s.ID)
+	}
+	for _, n := range b.Notes {
+		b.useID(n.ID)
+	}
+
+	// With two sections sharing an ID there is no telling which notes belonged
+	// to which, they all stay with the first one
+	seenSections := map[int]bool{}
+	for i := range b.SectionData {
+		if seenSections[b.SectionData[i].ID] {
+			b.SectionData[i].ID = b.NewID()
+			changed = true
+		}
+		seenSections[b.SectionData[i].ID] = true
+	}
+
+	seenNotes := map[int]bool{}
+	for _, n := range b.Notes {
+		if n.ID == 0 || seenNotes[n.ID] {
+			n.ID = b.NewID()
+			changed = true
+		}
+		seenNotes[n.ID] = true
+	}
+
+	return changed
+}
+
+// NoteByID finds a note anywhere on the board
+func (b *BoardState) NoteByID(id int) *Note {
+	for _, n := range b.Notes {
+		if n.ID == id {
+			return n
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRepairIDs(t *testing.T) {
+	tests := []struct {
+		name         string
+		sections     []int
+		notes        []int
+		wantSections []int
+		wantNotes    []int
+		wantNextID   int
+		wantChanged  bool
+	}{
+		{"nothing to repair", []int{0, 1}, []int{2, 3}, []int{0, 1}, []int{2, 3}, 4, false},
+		{"notes without IDs", []int{0, 1}, []int{0, 0}, []int{0, 1}, []int{2, 3}, 4, true},
+		{"notes sharing an ID", []int{0}, []int{5, 5, 2}, []int{0}, []int{5, 6, 2}, 7, true},
+		{"sections sharing an ID", []int{0, 0, 3}, []int{4}, []int{0, 5, 3}, []int{4}, 6, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := BoardState{}
+			for i, id := range tt.sections {
+				b.SectionData = append(b.SectionData, Section{ID: id, Order: i})
+			}
+			for i, id := range tt.notes {
+				b.Notes = append(b.Notes, &Note{ID: id, Order: i})
+			}
+
+			if changed := b.RepairIDs(); changed != tt.wantChanged {
+				t.Errorf("changed is %v, want %v", changed, tt.wantChanged)
+			}
+			sections, notes := []int{}, []int{}
+			for _, s := range b.SectionData {
+				sections = append(sections, s.ID)
+			}
+			for _, n := range b.Notes {
+				notes = append(notes, n.ID)
+			}
+			if !slices.Equal(sections, tt.wantSections) || !slices.Equal(notes, tt.wantNotes) {
+				t.Errorf("sections %v and notes %v, want %v and %v", sections, notes, tt.wantSections, tt.wantNotes)
+			}
+			if b.NextID != tt.wantNextID {
+				t.Errorf("NextID is %d, want %d", b.NextID, tt.wantNextID)
+			}
+		})
+	}
+}
+
+func TestNewIDSkipsZero(t *testing.T) {
+	b := BoardState{}
+	if a, c := b.NewID(), b.NewID(); a != 1 || c != 2 {
+		t.Errorf("handed out %d and %d, want 1 and 2", a, c)
+	}
+}
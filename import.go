@@ -0,0 +1,323 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Every importer reads its file into an ImportedBoard, ImportBoardCmd then
+// merges it into the board or replaces the board with it. Going through a
+// command makes an import undoable and lets journals record it.
+
+// ImportedBoard is a board read from another tool, before it gets IDs and orders
+type ImportedBoard struct {
+	Sections []ImportedSection
+}
+
+// ImportedSection is matched with a section of the board by name. An empty
+// Name means the notes came without a section and go to the first one.
+type ImportedSection struct {
+	Name  string
+	Notes []ImportedNote
+}
+
+type ImportedNote struct {
+	Content     string
+	Description string
+	Tags        []string
+	Checked     bool
+	Due         time.Time
+	Created     time.Time // The time of the import when zero
+	CheckedAt   time.Time // The time of the import when zero and Checked
+}
+
+func (b ImportedBoard) noteCount() int {
+	count := 0
+	for _, s := range b.Sections {
+		count += len(s.Notes)
+	}
+	return count
+}
+
+// importers read a file of each format, by the name --format takes
+var importers = map[string]func(r io.Reader) (ImportedBoard, error){
+	"md":     parseMarkdownTasks,
+	"trello": parseTrello,
+	"github": parseGitHubProject,
+
+	todoTxtFormat: parseTodoTxt,
+}
+
+func importerNames() string {
+	return strings.Join(slices.Sorted(maps.Keys(importers)), ", ")
+}
+
+// importFormat picks the format from the extension of path
+func importFormat(path string) (string, error) {
+	ext := strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	switch ext {
+	case "markdown":
+		ext = "md"
+	case "txt":
+		ext = todoTxtFormat
+	}
+	if _, ok := importers[ext]; !ok {
+		return "", fmt.Errorf("can't tell the format of %s, pass --format with one of %s", path, importerNames())
+	}
+	return ext, nil
+}
+
+// importBoard applies ImportBoardCmd. Notes already in a section with the same
+// title are left as they are, so importing the same file twice adds nothing.
+// Only the notes from before the import count, the same title twice in the
+// file gives two notes.
+func (b *BoardState) importBoard(c ImportBoardCmd, at time.Time) error {
+	if len(c.Board.Sections) == 0 {
+		return ErrNothingToImport
+	}
+	if c.Replace {
+		b.SectionData = []Section{}
+		b.DeletedSections = []Section{}
+		b.Notes = []*Note{}
+	}
+
+	// Titles on the board before the import, by section
+	existing := map[int][]string{}
+	for _, n := range b.Notes {
+		if b.IsActive(n) {
+			existing[n.SectionID] = append(existing[n.SectionID], n.Content)
+		}
+	}
+
+	for _, imported := range c.Board.Sections {
+		section := b.importSection(imported.Name)
+		notes := b.NotesIn(section.ID)
+		for _, in := range imported.Notes {
+			if slices.Contains(existing[section.ID], in.Content) {
+				continue
+			}
+			note := NewNote(in.Content, len(notes), section.ID, b.NewID())
+			note.Description = in.Description
+			note.Tags = slices.Clone(in.Tags)
+			note.DueDate = in.Due
+			note.DateCreated = at
+			if !in.Created.IsZero() {
+				note.DateCreated = in.Created
+			}
+			note.DateUpdated = at
+			if in.Checked {
+				note.IsChecked = true
+				note.DateChecked = at
+				if !in.CheckedAt.IsZero() {
+					note.DateChecked = in.CheckedAt
+				}
+			}
+			b.Notes = append(b.Notes, note)
+			notes = append(notes, note)
+		}
+	}
+	return nil
+}
+
+// importSection finds the section called name, ignoring case and taking
+// dashes for spaces ("to-do" is "To Do"), or adds it on the right. An empty
+// name is the first section.
+func (b *BoardState) importSection(name string) *Section {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		if first, ok := FindSectionDataByOrder(b.SectionData, 0); ok {
+			return first
+		}
+		name = "Inbox"
+	}
+	for i := range b.SectionData {
+		if strings.EqualFold(strings.ReplaceAll(b.SectionData[i].Name, " ", "-"), strings.ReplaceAll(name, " ", "-")) {
+			return &b.SectionData[i]
+		}
+	}
+	b.SectionData = append(b.SectionData, NewSection(name, len(b.SectionData), b.NewID()))
+	return &b.SectionData[len(b.SectionData)-1]
+}
+
+// parseMarkdownTasks reads "## Section" headings and "- [ ] item" lines. A
+// level one heading is the title of the file and is skipped. Indented lines
+// under an item become its description. Trailing #tags and "(due 2026-10-20)"
+// are read back the way exportMarkdown writes them.
+func parseMarkdownTasks(r io.Reader) (ImportedBoard, error) {
+	board := ImportedBoard{}
+	var note *ImportedNote
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		trimmed := strings.TrimLeft(line, " \t")
+		indented := len(trimmed) < len(line)
+
+		if level, title, ok := markdownHeading(trimmed); ok && !indented {
+			note = nil
+			if level > 1 {
+				board.Sections = append(board.Sections, ImportedSection{Name: title})
+			}
+			continue
+		}
+
+		if checked, text, ok := markdownTask(trimmed); ok {
+			if len(board.Sections) == 0 {
+				board.Sections = append(board.Sections, ImportedSection{})
+			}
+			section := &board.Sections[len(board.Sections)-1]
+			section.Notes = append(section.Notes, parseTaskTitle(text, checked))
+			note = &section.Notes[len(section.Notes)-1]
+			continue
+		}
+
+		switch {
+		case trimmed == "":
+		case note != nil && indented:
+			if note.Description != "" {
+				note.Description += "\n"
+			}
+			note.Description += trimmed
+		default:
+			// Anything else between items ends the description
+			note = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return ImportedBoard{}, err
+	}
+	if len(board.Sections) == 0 {
+		return ImportedBoard{}, errors.New(`no "## Section" headings or "- [ ] item" lines found`)
+	}
+	return board, nil
+}
+
+// markdownHeading reads "## Title", with or without closing #s
+func markdownHeading(line string) (int, string, bool) {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || (len(line) > level && line[level] != ' ') {
+		return 0, "", false
+	}
+	title := strings.TrimSpace(strings.TrimRight(strings.TrimSpace(line[level:]), "#"))
+	if title == "" {
+		title = "Unnamed Section"
+	}
+	return level, title, true
+}
+
+// markdownTask reads "- [ ] text" and "- [x] text", with -, * or + as the bullet
+func markdownTask(line string) (bool, string, bool) {
+	if len(line) < 2 || !strings.ContainsRune("-*+", rune(line[0])) || line[1] != ' ' {
+		return false, "", false
+	}
+	rest := strings.TrimLeft(line[2:], " ")
+	var checked bool
+	switch {
+	case strings.HasPrefix(rest, "[ ] "):
+	case strings.HasPrefix(rest, "[x] "), strings.HasPrefix(rest, "[X] "):
+		checked = true
+	default:
+		return false, "", false
+	}
+	text := strings.TrimSpace(rest[4:])
+	return checked, text, text != ""
+}
+
+// parseTaskTitle splits the trailing due date and #tags off the text of an item.
+// Tags have to start with a letter so issue numbers like #123 stay in the title.
+func parseTaskTitle(text string, checked bool) ImportedNote {
+	note := ImportedNote{Content: text, Checked: checked}
+
+	if i := strings.LastIndex(text, " (due "); i != -1 && strings.HasSuffix(text, ")") {
+		due, err := time.ParseInLocation(time.DateOnly, text[i+len(" (due "):len(text)-1], time.Local)
+		if err == nil {
+			note.Due = due
+			text = text[:i]
+		}
+	}
+
+	words := strings.Fields(text)
+	tags := []string{}
+	for len(words) > 1 {
+		last := []rune(words[len(words)-1])
+		if len(last) < 2 || last[0] != '#' || !unicode.IsLetter(last[1]) {
+			break
+		}
+		tags = append([]string{string(last)}, tags...)
+		words = words[:len(words)-1]
+	}
+	if len(tags) > 0 {
+		note.Tags = parseTags(strings.Join(tags, " "))
+		text = strings.Join(words, " ")
+	}
+
+	note.Content = text
+	return note
+}
+
+func runImport(board *headlessBoard, args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	format := fs.String("format", "", "format of the file, one of "+importerNames()+". Picked from the file's extension when empty")
+	replace := fs.Bool("replace", false, "replace the whole board, trash and archive included, instead of merging into it")
+	// Let the file come first, the flag package stops at the first argument
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		args = append(slices.Clone(args[1:]), args[0])
+	}
+	rest, err := parseArgs(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	path := rest[0]
+
+	if *format == "" {
+		if path == "-" {
+			return usageError{"--format is required when reading from stdin"}
+		}
+		if *format, err = importFormat(path); err != nil {
+			return usageError{err.Error()}
+		}
+	}
+	parse, ok := importers[*format]
+	if !ok {
+		return usageError{fmt.Sprintf("unknown format %q, use one of %s", *format, importerNames())}
+	}
+
+	in := io.Reader(os.Stdin)
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+	imported, err := parse(in)
+	if err != nil {
+		return fmt.Errorf("could not read %s: %w", path, err)
+	}
+
+	before := len(board.Notes)
+	if *replace {
+		before = 0
+	}
+	if err := board.Apply(ImportBoardCmd{Board: imported, Replace: *replace}); err != nil {
+		return err
+	}
+	added := len(board.Notes) - before
+	fmt.Fprintf(out, "Imported %s", plural(added, "note"))
+	if skipped := imported.noteCount() - added; skipped > 0 {
+		fmt.Fprintf(out, ", skipped %d already on the board", skipped)
+	}
+	fmt.Fprintln(out)
+	return nil
+}
@@ -0,0 +1,245 @@
+package main
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// checkImport runs parse on input and compares what it read with want
+func checkImport(t *testing.T, parse func(r io.Reader) (ImportedBoard, error), input string, want ImportedBoard, wantErr bool) {
+	t.Helper()
+	got, err := parse(strings.NewReader(input))
+	if (err != nil) != wantErr {
+		t.Fatalf("error %v, want error %v", err, wantErr)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got  %+v\nwant %+v", got, want)
+	}
+}
+
+func TestParseMarkdownTasks(t *testing.T) {
+	due := time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name    string
+		input   string
+		want    ImportedBoard
+		wantErr bool
+	}{
+		{
+			"sections",
+			"# board\n\n## To Do\n\n- [ ] a\n* [x] b\n\n## Done ##\n\n+ [X] c\n",
+			ImportedBoard{Sections: []ImportedSection{
+				{Name: "To Do", Notes: []ImportedNote{{Content: "a"}, {Content: "b", Checked: true}}},
+				{Name: "Done", Notes: []ImportedNote{{Content: "c", Checked: true}}},
+			}},
+			false,
+		},
+		{
+			"items without a section",
+			"- [ ] a\n- [ ] b\n",
+			ImportedBoard{Sections: []ImportedSection{{Notes: []ImportedNote{{Content: "a"}, {Content: "b"}}}}},
+			false,
+		},
+		{
+			"empty section",
+			"## To Do\n\n_Nothing here_\n\n##\n- [ ] a\n",
+			ImportedBoard{Sections: []ImportedSection{{Name: "To Do"}, {Name: "Unnamed Section", Notes: []ImportedNote{{Content: "a"}}}}},
+			false,
+		},
+		{
+			"tags and due date",
+			"- [ ] Fix #123 for good #Bug #backend (due 2026-10-20)\n- [ ] #solo\n- [ ] Ship (due someday)\n",
+			ImportedBoard{Sections: []ImportedSection{{Notes: []ImportedNote{
+				{Content: "Fix #123 for good", Tags: []string{"bug", "backend"}, Due: due},
+				{Content: "#solo"},
+				{Content: "Ship (due someday)"},
+			}}}},
+			false,
+		},
+		{
+			"descriptions",
+			"- [ ] a\n  first line\n\tsecond line\n\n  after a blank line\nnot indented\n  not a description\n- [ ] b\n",
+			ImportedBoard{Sections: []ImportedSection{{Notes: []ImportedNote{
+				{Content: "a", Description: "first line\nsecond line\nafter a blank line"},
+				{Content: "b"},
+			}}}},
+			false,
+		},
+		{
+			"not tasks",
+			"- a list item\n-[ ] no space\n- [ ] \n  ## indented heading\n#hashtag\n",
+			ImportedBoard{},
+			true,
+		},
+		{"empty", "", ImportedBoard{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkImport(t, parseMarkdownTasks, tt.input, tt.want, tt.wantErr)
+		})
+	}
+}
+
+// Exporting and importing again keeps the sections, titles, tags, due dates and descriptions
+func TestMarkdownRoundTrip(t *testing.T) {
+	want := testBoard()
+	for _, cmd := range []Command{
+		AddNoteCmd{SectionID: inbox, Content: "Fix #123"},
+		TagNoteCmd{SectionID: inbox, Order: 0, Tags: []string{"bug", "backend"}},
+		DueNoteCmd{SectionID: inbox, Order: 0, DueDate: time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)},
+		DescribeNoteCmd{SectionID: inbox, Order: 0, Description: "first line\nsecond line"},
+		AddNoteCmd{SectionID: doing, Content: "Ship it"},
+		ToggleNoteCmd{SectionID: doing, Order: 0},
+	} {
+		if err := want.Apply(cmd, testNow); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var text strings.Builder
+	if err := exportMarkdown(&text, newExport("board", &want, func(n *Note) bool { return true }, testNow)); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := parseMarkdownTasks(strings.NewReader(text.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := testBoard()
+	if err := got.Apply(ImportBoardCmd{Board: imported, Replace: true}, testNow); err != nil {
+		t.Fatal(err)
+	}
+	if layout(&got) != layout(&want) {
+		t.Fatalf("got  %q\nwant %q", layout(&got), layout(&want))
+	}
+	g, w := got.NoteAt(got.SectionData[0].ID, 0), want.NoteAt(inbox, 0)
+	if !slices.Equal(g.Tags, w.Tags) || !g.DueDate.Equal(w.DueDate) || g.Description != w.Description {
+		t.Errorf("got %+v, want %+v", *g, *w)
+	}
+}
+
+func TestImportBoard(t *testing.T) {
+	notes := func(titles ...string) []ImportedNote {
+		notes := []ImportedNote{}
+		for _, title := range titles {
+			notes = append(notes, ImportedNote{Content: title})
+		}
+		return notes
+	}
+	// The board starts as "Inbox: a; Doing: b"
+	tests := []struct {
+		name    string
+		replace bool
+		board   ImportedBoard
+		want    string
+		wantErr error
+	}{
+		{"no section goes to the first one", false, ImportedBoard{Sections: []ImportedSection{{Notes: notes("c")}}}, "Inbox: a c; Doing: b", nil},
+		{"sections are matched by name", false, ImportedBoard{Sections: []ImportedSection{{Name: "DOING", Notes: notes("c")}}}, "Inbox: a; Doing: b c", nil},
+		{"new sections go on the right", false, ImportedBoard{Sections: []ImportedSection{{Name: "Done", Notes: notes("c")}}}, "Inbox: a; Doing: b; Done: c", nil},
+		{"notes already there are skipped", false, ImportedBoard{Sections: []ImportedSection{{Name: "Doing", Notes: notes("a", "b", "c", "c")}}}, "Inbox: a; Doing: b a c c", nil},
+		{"notes imported just before aren't", false, ImportedBoard{Sections: []ImportedSection{{Notes: notes("c")}, {Name: "inbox", Notes: notes("a", "c")}}}, "Inbox: a c c; Doing: b", nil},
+		{"replace", true, ImportedBoard{Sections: []ImportedSection{{Name: "Later", Notes: notes("a")}}}, "Later: a", nil},
+		{"replace keeps every note", true, ImportedBoard{Sections: []ImportedSection{{Name: "Later", Notes: notes("a", "a")}}}, "Later: a a", nil},
+		{"replace without a section", true, ImportedBoard{Sections: []ImportedSection{{Notes: notes("a")}}}, "Inbox: a", nil},
+		{"nothing", false, ImportedBoard{}, "Inbox: a; Doing: b", ErrNothingToImport},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := testBoard()
+			b.Apply(AddNoteCmd{SectionID: inbox, Content: "a"}, testNow)
+			b.Apply(AddNoteCmd{SectionID: doing, Content: "b"}, testNow)
+
+			err := b.Apply(ImportBoardCmd{Board: tt.board, Replace: tt.replace}, testNow)
+			if err != tt.wantErr {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if got := layout(&b); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportedNoteDates(t *testing.T) {
+	created := testNow.AddDate(0, -1, 0)
+	checked := testNow.AddDate(0, 0, -1)
+	b := testBoard()
+	err := b.Apply(ImportBoardCmd{Board: ImportedBoard{Sections: []ImportedSection{{Notes: []ImportedNote{
+		{Content: "a"},
+		{Content: "b", Checked: true},
+		{Content: "c", Checked: true, Created: created, CheckedAt: checked},
+	}}}}}, testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		content     string
+		created     time.Time
+		dateChecked time.Time
+	}{
+		{"a", testNow, time.Time{}},
+		{"b", testNow, testNow},
+		{"c", created, checked},
+	}
+	for i, tt := range tests {
+		n := b.NoteAt(inbox, i)
+		if n.Content != tt.content || !n.DateCreated.Equal(tt.created) || !n.DateChecked.Equal(tt.dateChecked) {
+			t.Errorf("note %d is %q created %s and checked %s", i, n.Content, n.DateCreated, n.DateChecked)
+		}
+	}
+}
+
+func TestImportFormat(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{"backlog.md", "md", false},
+		{"BACKLOG.Markdown", "md", false},
+		{"todo.txt", todoTxtFormat, false},
+		{"board.json", "", true},
+		{"backlog", "", true},
+	}
+	for _, tt := range tests {
+		got, err := importFormat(tt.path)
+		if got != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("importFormat(%q) = %q, %v", tt.path, got, err)
+		}
+	}
+}
+
+func TestRunImportReportsSkipped(t *testing.T) {
+	dir := t.TempDir()
+	cfg := Config{BoardDir: dir, BoardName: "board", Format: JSONFormat}
+	path := filepath.Join(dir, "backlog.md")
+	if err := os.WriteFile(path, []byte("- [ ] a\n- [ ] b\n- [ ] b\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	runSubcommand(cfg, []string{"add", "a"}, io.Discard, io.Discard)
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"import", path}, "Imported 2 notes, skipped 1 already on the board\n"},
+		{[]string{"import", "--replace", path}, "Imported 3 notes\n"},
+	}
+	for _, tt := range tests {
+		var out, errOut strings.Builder
+		if code := runSubcommand(cfg, tt.args, &out, &errOut); code != 0 {
+			t.Fatalf("%v exited with %d: %s", tt.args, code, errOut.String())
+		}
+		if out.String() != tt.want {
+			t.Errorf("%v printed %q, want %q", tt.args, out.String(), tt.want)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"time"
+)
+
+const (
+	journalExt = ".jsonl"
+	// How many events can pile up after the last snapshot before Save compacts
+	// the journal. Compacting drops the older events from the audit trail.
+	defaultCompactEvery = 500
+	snapshotKind        = "Snapshot"
+)
+
+// Event is one line of a journal. It holds either a Command or, for
+// snapshots, the whole board in the same versioned shape as a board file.
+type Event struct {
+	At      time.Time       `json:"at"`
+	User    string          `json:"user,omitempty"`
+	Kind    string          `json:"kind"`
+	Command json.RawMessage `json:"command,omitempty"`
+	Board   json.RawMessage `json:"board,omitempty"`
+}
+
+// Journal is implemented by stores that persist every change as it happens
+// instead of rewriting the board on Save.
+type Journal interface {
+	Append(cmd Command, at time.Time) error
+	// Pending is true when an Append failed and only Save can get the board
+	// on disk again
+	Pending() bool
+}
+
+// JournalStore keeps a board as an append-only JSON-lines file of events.
+// Loading replays the events onto a blank board. Save only compacts the file
+// into a single snapshot once enough events have piled up, the changes
+// themselves are already on disk through Append.
+type JournalStore struct {
+	Path         string
+	CompactEvery int // 0 never compacts, keeping the full history
+
+	sinceSnapshot int  // Events after the last snapshot in the file
+	mustCompact   bool // An Append failed, the file is missing changes
+	undoable      int  // How many undo events Load could replay, after the last snapshot
+	redoable      int  // Same for redo events
+	watcher       fileWatcher
+}
+
+func NewJournalStore(path string) *JournalStore {
+	return &JournalStore{Path: path, CompactEvery: defaultCompactEvery}
+}
+
+var commandDecoders = map[string]func(json.RawMessage) (Command, error){
+	AddNoteCmd{}.Kind():        decodeCommand[AddNoteCmd],
+	EditNoteCmd{}.Kind():       decodeCommand[EditNoteCmd],
+	DescribeNoteCmd{}.Kind():   decodeCommand[DescribeNoteCmd],
+	TagNoteCmd{}.Kind():        decodeCommand[TagNoteCmd],
+	DueNoteCmd{}.Kind():        decodeCommand[DueNoteCmd],
+	ToggleNoteCmd{}.Kind():     decodeCommand[ToggleNoteCmd],
+	DeleteNoteCmd{}.Kind():     decodeCommand[DeleteNoteCmd],
+	MoveNoteCmd{}.Kind():       decodeCommand[MoveNoteCmd],
+	AddSectionCmd{}.Kind():     decodeCommand[AddSectionCmd],
+	RenameSectionCmd{}.Kind():  decodeCommand[RenameSectionCmd],
+	DeleteSectionCmd{}.Kind():  decodeCommand[DeleteSectionCmd],
+	SortByDueCmd{}.Kind():      decodeCommand[SortByDueCmd],
+	MoveSectionCmd{}.Kind():    decodeCommand[MoveSectionCmd],
+	SaveViewCmd{}.Kind():       decodeCommand[SaveViewCmd],
+	DeleteViewCmd{}.Kind():     decodeCommand[DeleteViewCmd],
+	RestoreNoteCmd{}.Kind():    decodeCommand[RestoreNoteCmd],
+	RestoreSectionCmd{}.Kind(): decodeCommand[RestoreSectionCmd],
+	PurgeNoteCmd{}.Kind():      decodeCommand[PurgeNoteCmd],
+	PurgeSectionCmd{}.Kind():   decodeCommand[PurgeSectionCmd],
+	PurgeTrashCmd{}.Kind():     decodeCommand[PurgeTrashCmd],
+	ArchiveNoteCmd{}.Kind():    decodeCommand[ArchiveNoteCmd],
+	ArchiveCheckedCmd{}.Kind(): decodeCommand[ArchiveCheckedCmd],
+	ArchiveOlderCmd{}.Kind():   decodeCommand[ArchiveOlderCmd],
+	UnarchiveNoteCmd{}.Kind():  decodeCommand[UnarchiveNoteCmd],
+	ImportBoardCmd{}.Kind():    decodeCommand[ImportBoardCmd],
+	ReplaceBoardCmd{}.Kind():   decodeCommand[ReplaceBoardCmd],
+}
+
+func decodeCommand[T Command](data json.RawMessage) (Command, error) {
+	var cmd T
+	err := json.Unmarshal(data, &cmd)
+	return cmd, err
+}
+
+// EventFor wraps cmd in an Event ready to be written down
+func EventFor(cmd Command, at time.Time) (Event, error) {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return Event{}, err
+	}
+	return Event{At: at, User: currentUser(), Kind: cmd.Kind(), Command: data}, nil
+}
+
+func snapshotEvent(state BoardState, at time.Time) (Event, error) {
+	data, err := encodeBoard(state)
+	if err != nil {
+		return Event{}, err
+	}
+	// One event per line, the indented board would span many
+	var compact bytes.Buffer
+	if err := json.Compact(&compact, data); err != nil {
+		return Event{}, err
+	}
+	return Event{At: at, User: currentUser(), Kind: snapshotKind, Board: compact.Bytes()}, nil
+}
+
+func currentUser() string {
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return os.Getenv("USER")
+}
+
+// Replay applies ev on top of state. Undo and redo events step through
+// history, the boards from before each change.
+func (ev Event) Replay(state *BoardState, history *journalHistory) error {
+	switch ev.Kind {
+	case snapshotKind:
+		board, err := decodeBoard(ev.Board)
+		if err != nil {
+			return err
+		}
+		*state = board
+		// Undo never goes back past a snapshot, see JournalStore.Append
+		*history = journalHistory{}
+		return nil
+
+	case UndoCmd{}.Kind():
+		return history.step(&history.undo, &history.redo, state)
+
+	case RedoCmd{}.Kind():
+		return history.step(&history.redo, &history.undo, state)
+	}
+
+	decode, ok := commandDecoders[ev.Kind]
+	if !ok {
+		return fmt.Errorf("unknown event kind %q", ev.Kind)
+	}
+	cmd, err := decode(ev.Command)
+	if err != nil {
+		return err
+	}
+	before := CloneBoardState(*state)
+	if err := state.Apply(cmd, ev.At); err != nil {
+		return err
+	}
+	history.undo = append(history.undo, before)
+	if len(history.undo) > historyLimit {
+		history.undo = history.undo[1:]
+	}
+	history.redo = nil
+	return nil
+}
+
+// journalHistory follows the undo and redo events of a journal the way History
+// follows undo and redo in the UI
+type journalHistory struct {
+	undo []BoardState
+	redo []BoardState
+}
+
+// step puts back the newest board of from, keeping the current one on to
+func (h *journalHistory) step(from, to *[]BoardState, state *BoardState) error {
+	if len(*from) == 0 {
+		return errors.New("nothing to undo or redo")
+	}
+	last := len(*from) - 1
+	*to = append(*to, *state)
+	*state = (*from)[last]
+	*from = (*from)[:last]
+	return nil
+}
+
+func (s *JournalStore) Load() (BoardState, error) {
+	data, err := os.ReadFile(s.Path)
+	if err != nil {
+		return BoardState{}, err
+	}
+
+	state := LoadBlankProgramState().BoardState
+	history := journalHistory{}
+	s.sinceSnapshot = 0
+
+	lines := bytes.Split(data, []byte("\n"))
+	for i, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+
+		var ev Event
+		if err := json.Unmarshal(line, &ev); err != nil {
+			// A crash in the middle of an Append leaves a partial last line. The
+			// change was never confirmed so drop it, before the next Append
+			// would glue itself onto it.
+			if i == len(lines)-1 {
+				if err := os.Truncate(s.Path, int64(len(data)-len(line))); err != nil {
+					return BoardState{}, err
+				}
+				break
+			}
+			return BoardState{}, &CorruptBoardError{Path: s.Path, Err: fmt.Errorf("line %d: %w", i+1, err)}
+		}
+
+		if err := ev.Replay(&state, &history); err != nil {
+			var newer *NewerSchemaError
+			if errors.As(err, &newer) {
+				return BoardState{}, err
+			}
+			return BoardState{}, &CorruptBoardError{Path: s.Path, Err: fmt.Errorf("line %d: %w", i+1, err)}
+		}
+
+		if ev.Kind == snapshotKind {
+			s.sinceSnapshot = 0
+		} else {
+			s.sinceSnapshot++
+		}
+	}
+
+	state.RepairIDs()
+	s.undoable, s.redoable = len(history.undo), len(history.redo)
+	s.watcher.remember(s.Path)
+	return state, nil
+}
+
+func (s *JournalStore) Append(cmd Command, at time.Time) error {
+	if s.mustCompact {
+		// The file is missing a change already, events after it would replay wrong
+		return nil
+	}
+	// An undo reaching back past the last snapshot can't be replayed. Leave it
+	// to Save, like a failed Append.
+	switch cmd.(type) {
+	case UndoCmd:
+		if s.undoable == 0 {
+			s.mustCompact = true
+			return nil
+		}
+	case RedoCmd:
+		if s.redoable == 0 {
+			s.mustCompact = true
+			return nil
+		}
+	}
+
+	ev, err := EventFor(cmd, at)
+	if err != nil {
+		return err
+	}
+	if err := s.appendEvent(ev); err != nil {
+		s.mustCompact = true
+		return err
+	}
+	s.sinceSnapshot++
+
+	switch cmd.(type) {
+	case UndoCmd:
+		s.undoable--
+		s.redoable++
+	case RedoCmd:
+		s.redoable--
+		s.undoable++
+	default:
+		s.undoable = min(s.undoable+1, historyLimit)
+		s.redoable = 0
+	}
+	return nil
+}
+
+func (s *JournalStore) Pending() bool {
+	return s.mustCompact
+}
+
+func (s *JournalStore) appendEvent(ev Event) error {
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	s.watcher.remember(s.Path)
+	return nil
+}
+
+// Save compacts the journal when it got long, when it doesn't exist yet or
+// when an earlier Append failed. Otherwise every change is already written.
+func (s *JournalStore) Save(state BoardState) error {
+	_, err := os.Stat(s.Path)
+	long := s.CompactEvery > 0 && s.sinceSnapshot >= s.CompactEvery
+	if err == nil && !s.mustCompact && !long {
+		return nil
+	}
+	return s.Compact(state)
+}
+
+// Compact replaces the whole journal with a single snapshot of state
+func (s *JournalStore) Compact(state BoardState) error {
+	ev, err := snapshotEvent(state, time.Now())
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(ev)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.Path, append(line, '\n'), 0600); err != nil {
+		return err
+	}
+
+	s.sinceSnapshot = 0
+	s.mustCompact = false
+	s.undoable, s.redoable = 0, 0
+	s.watcher.remember(s.Path)
+	return nil
+}
+
+func (s *JournalStore) Watch() (<-chan struct{}, error) {
+	return s.watcher.watch(s.Path), nil
+}
+
+func (s *JournalStore) Close() error {
+	s.watcher.close()
+	return nil
+}
+
+// journal writes cmd down right away when the store keeps a journal
+func (m *ProgramModel) journal(cmd Command, at time.Time) {
+	j, ok := m.Store.(Journal)
+	if !ok {
+		return
+	}
+	if err := j.Append(cmd, at); err != nil {
+		m.StatusText = "Could not write to the journal: " + err.Error()
+	}
+}
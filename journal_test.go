@@ -0,0 +1,286 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// sameBoard fails t when the notes of got and want differ in anything a journal keeps
+func sameBoard(t *testing.T, got, want *BoardState) {
+	t.Helper()
+	if layout(got) != layout(want) {
+		t.Fatalf("got  %q\nwant %q", layout(got), layout(want))
+	}
+	if got.NextID != want.NextID {
+		t.Errorf("NextID is %d, want %d", got.NextID, want.NextID)
+	}
+	for i, w := range want.Notes {
+		g := got.Notes[i]
+		switch {
+		case g.ID != w.ID || g.Order != w.Order || g.SectionID != w.SectionID || g.Description != w.Description || !slices.Equal(g.Tags, w.Tags):
+			t.Errorf("note %d is %+v, want %+v", i, *g, *w)
+		case !g.DueDate.Equal(w.DueDate) || !g.DateCreated.Equal(w.DateCreated) || !g.DateUpdated.Equal(w.DateUpdated) || !g.DateChecked.Equal(w.DateChecked):
+			t.Errorf("note %d has the dates %+v, want %+v", i, *g, *w)
+		}
+	}
+}
+
+func TestJournalReplay(t *testing.T) {
+	due := time.Date(2026, 11, 1, 0, 0, 0, 0, time.Local)
+	replacement := testBoard()
+	replacement.Apply(AddNoteCmd{SectionID: doing, Content: "z"}, testNow)
+
+	tests := []struct {
+		name string
+		cmds []Command
+	}{
+		{"notes", []Command{
+			AddNoteCmd{SectionID: inbox, Content: "a"},
+			AddNoteCmd{SectionID: inbox, Content: "b"},
+			EditNoteCmd{SectionID: inbox, Order: 0, Content: "A"},
+			DescribeNoteCmd{SectionID: inbox, Order: 0, Description: "first line\nsecond line"},
+			TagNoteCmd{SectionID: inbox, Order: 0, Tags: []string{"docs", "urgent"}},
+			DueNoteCmd{SectionID: inbox, Order: 1, DueDate: due},
+			ToggleNoteCmd{SectionID: inbox, Order: 1},
+			MoveNoteCmd{SectionID: inbox, Order: 1, ToSectionID: doing, ToOrder: -1},
+			SortByDueCmd{SectionID: inbox},
+			AddNoteCmd{SectionID: inbox, Content: "c"},
+			DeleteNoteCmd{SectionID: inbox, Order: 0},
+		}},
+		{"sections", []Command{
+			AddSectionCmd{ID: 5, Name: "Done"},
+			AddNoteCmd{SectionID: 5, Content: "a"},
+			RenameSectionCmd{SectionID: 5, Name: "Shipped"},
+			MoveSectionCmd{SectionID: 5, ToOrder: 0},
+			DeleteSectionCmd{SectionID: doing},
+			RestoreSectionCmd{ID: doing},
+			DeleteSectionCmd{SectionID: inbox},
+		}},
+		{"trash and archive", []Command{
+			AddNoteCmd{SectionID: inbox, Content: "a"},
+			AddNoteCmd{SectionID: inbox, Content: "b"},
+			AddNoteCmd{SectionID: inbox, Content: "c"},
+			AddNoteCmd{SectionID: doing, Content: "d"},
+			DeleteNoteCmd{SectionID: inbox, Order: 0},
+			ToggleNoteCmd{SectionID: inbox, Order: 0},
+			ArchiveCheckedCmd{SectionID: inbox},
+			RestoreNoteCmd{ID: 2},
+			UnarchiveNoteCmd{ID: 3},
+			DeleteNoteCmd{SectionID: inbox, Order: 0},
+			PurgeNoteCmd{ID: 4},
+			ArchiveNoteCmd{SectionID: doing, Order: 0},
+			DeleteSectionCmd{SectionID: doing},
+			PurgeSectionCmd{ID: doing},
+			DeleteNoteCmd{SectionID: inbox, Order: 0},
+			PurgeTrashCmd{},
+		}},
+		{"views", []Command{
+			SaveViewCmd{Name: "mine", Query: "tag:me"},
+			SaveViewCmd{Name: "late", Query: "is:overdue"},
+			DeleteViewCmd{Name: "mine"},
+		}},
+		{"import", []Command{
+			AddNoteCmd{SectionID: inbox, Content: "a"},
+			ImportBoardCmd{Board: ImportedBoard{Sections: []ImportedSection{
+				{Name: "", Notes: []ImportedNote{{Content: "a"}, {Content: "b", Tags: []string{"x"}, Due: due}}},
+				{Name: "Done", Notes: []ImportedNote{{Content: "c", Checked: true, Created: due.AddDate(0, -1, 0), CheckedAt: due}}},
+			}}},
+		}},
+		{"import replacing the board", []Command{
+			AddNoteCmd{SectionID: inbox, Content: "a"},
+			ImportBoardCmd{Replace: true, Board: ImportedBoard{Sections: []ImportedSection{
+				{Name: "Later", Notes: []ImportedNote{{Content: "b", Description: "more"}}},
+			}}},
+		}},
+		{"replace", []Command{
+			AddNoteCmd{SectionID: inbox, Content: "a"},
+			ReplaceBoardCmd{Board: replacement},
+			AddNoteCmd{SectionID: inbox, Content: "b"},
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "board.jsonl")
+			store := NewJournalStore(path)
+			want := testBoard()
+			if err := store.Compact(want); err != nil {
+				t.Fatal(err)
+			}
+			at := testNow
+			for _, cmd := range tt.cmds {
+				at = at.Add(time.Minute)
+				if err := want.Apply(cmd, at); err != nil {
+					t.Fatalf("%s: %v", cmd.Kind(), err)
+				}
+				if err := store.Append(cmd, at); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			got, err := NewJournalStore(path).Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sameBoard(t, &got, &want)
+		})
+	}
+}
+
+func TestJournalLoad(t *testing.T) {
+	snapshot, err := snapshotEvent(testBoard(), testNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newer := snapshot
+	newer.Board = []byte(`{"schemaVersion":99}`)
+	null := snapshot
+	null.Board = []byte(`null`)
+	add, _ := EventFor(AddNoteCmd{SectionID: doing, Content: "a"}, testNow)
+	missing, _ := EventFor(AddNoteCmd{SectionID: 7, Content: "a"}, testNow)
+	unknown := add
+	unknown.Kind = "Frobnicate"
+	undo, _ := EventFor(UndoCmd{}, testNow)
+	redo, _ := EventFor(RedoCmd{}, testNow)
+	// What a crash in the middle of an Append leaves behind
+	const partial = `{"at":"2026-10-`
+
+	tests := []struct {
+		name    string
+		lines   []any // Events, or strings written as they are
+		want    string
+		wantErr any // A pointer to the error type Load returns
+	}{
+		{"empty", nil, "Inbox", nil},
+		{"events on a blank board", []any{add}, "", &CorruptBoardError{}},
+		{"snapshot", []any{snapshot}, "Inbox; Doing", nil},
+		{"events after a snapshot", []any{snapshot, add, add}, "Inbox; Doing: a a", nil},
+		{"a later snapshot wins", []any{snapshot, add, snapshot, add}, "Inbox; Doing: a", nil},
+		{"blank lines", []any{"", snapshot, "  ", add, ""}, "Inbox; Doing: a", nil},
+		{"partial last line", []any{snapshot, add, partial}, "Inbox; Doing: a", nil},
+		{"broken line in the middle", []any{snapshot, partial, add}, "", &CorruptBoardError{}},
+		{"unknown kind", []any{snapshot, unknown}, "", &CorruptBoardError{}},
+		{"command that fails", []any{snapshot, missing}, "", &CorruptBoardError{}},
+		{"undo and redo", []any{snapshot, add, add, undo, undo, redo}, "Inbox; Doing: a", nil},
+		{"undo stops at a snapshot", []any{snapshot, add, snapshot, undo}, "", &CorruptBoardError{}},
+		{"redo after a change", []any{snapshot, add, undo, add, redo}, "", &CorruptBoardError{}},
+		{"newer schema", []any{newer}, "", &NewerSchemaError{}},
+		{"null snapshot", []any{snapshot, add, null}, "", &CorruptBoardError{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			text := ""
+			for i, line := range tt.lines {
+				switch l := line.(type) {
+				case string:
+					text += l
+				case Event:
+					data, err := json.Marshal(l)
+					if err != nil {
+						t.Fatal(err)
+					}
+					text += string(data)
+				}
+				if line != partial || i < len(tt.lines)-1 {
+					text += "\n"
+				}
+			}
+			path := filepath.Join(t.TempDir(), "board.jsonl")
+			if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := NewJournalStore(path).Load()
+			switch want := tt.wantErr.(type) {
+			case nil:
+				if err != nil {
+					t.Fatal(err)
+				}
+			case *CorruptBoardError:
+				if !errors.As(err, &want) {
+					t.Fatalf("got %v, want a corrupt board", err)
+				}
+				return
+			case *NewerSchemaError:
+				if !errors.As(err, &want) {
+					t.Fatalf("got %v, want a newer schema", err)
+				}
+				return
+			}
+			if layout(&got) != tt.want {
+				t.Errorf("got %q, want %q", layout(&got), tt.want)
+			}
+
+			// The partial line is gone, so the next event starts on a line of its own
+			data, _ := os.ReadFile(path)
+			if len(data) > 0 && !strings.HasSuffix(string(data), "\n") {
+				t.Errorf("the journal ends in %q", data)
+			}
+		})
+	}
+}
+
+func TestJournalUndoRedo(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string
+	}{
+		{"undo", []string{"a", "+one", "enter", "a", "+two", "enter", "u"}},
+		{"undo everything", []string{"a", "+one", "enter", "e", "+s", "enter", "u", "u"}},
+		{"redo", []string{"a", "+one", "enter", "d", "u", "u", "ctrl+r", "ctrl+r"}},
+		{"change after an undo", []string{"a", "+one", "enter", "a", "+two", "enter", "u", "a", "+three", "enter", "u"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, err := initialModel(NewBoardRegistry(t.TempDir(), JournalFormat, 0), "board")
+			if err != nil {
+				t.Fatal(err)
+			}
+			m = play(m, tt.keys...)
+			if m.Dirty {
+				t.Error("dirty after undo and redo")
+			}
+
+			store := m.Store.(*JournalStore)
+			got, err := NewJournalStore(store.Path).Load()
+			if err != nil {
+				t.Fatal(err)
+			}
+			sameBoard(t, &got, &m.BoardState)
+			if data, _ := os.ReadFile(store.Path); strings.Contains(string(data), "ReplaceBoard") {
+				t.Errorf("the journal has the whole board again:\n%s", data)
+			}
+		})
+	}
+}
+
+// Undo can go back further than the last snapshot, Save has to write it down then
+func TestJournalUndoPastSnapshot(t *testing.T) {
+	m, err := initialModel(NewBoardRegistry(t.TempDir(), JournalFormat, 0), "board")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m = play(m, "a", "+one", "enter")
+	store := m.Store.(*JournalStore)
+	if err := store.Compact(m.BoardState); err != nil {
+		t.Fatal(err)
+	}
+
+	m = play(m, "u", "a", "+two", "enter")
+	if !m.Dirty {
+		t.Fatal("not dirty after an undo the journal can't replay")
+	}
+	if err := m.Save(); err != nil {
+		t.Fatal(err)
+	}
+	got, err := NewJournalStore(store.Path).Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameBoard(t, &got, &m.BoardState)
+}
@@ -5,20 +5,24 @@ package main
 // You may also need to run `go mod tidy` to download bubbletea and its
 // dependencies.
 import (
-	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"maps"
 	"os"
 	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	lg "github.com/charmbracelet/lipgloss"
 )
 
-var mockId = 10
+// Note titles and tags can be longer than the other inputs, they come from
+// the command line and imports too
+const noteCharLimit = 200
 
 func NewTextInputSetting() textinput.Model {
 	ti := textinput.New()
@@ -34,10 +38,26 @@ func (m ProgramModel) Init() tea.Cmd {
 	/*
 		Maybe check if there is section that I otherwise create the uncategorized one.
 	*/
-	return textinput.Blink
+	cmds := []tea.Cmd{textinput.Blink, waitForStoreChange(m.StoreEvents)}
+	if m.Dirty {
+		// The trash was purged or old notes archived on start
+		cmds = append(cmds, ScheduleAutoSave(m.ChangeSeq))
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m ProgramModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	// Every change restarts the auto-save timer, see ScheduleAutoSave
+	changeSeq := m.ChangeSeq
+	next, cmd := m.update(msg)
+	next.FollowCursor()
+	if next.ChangeSeq != changeSeq {
+		cmd = tea.Batch(cmd, ScheduleAutoSave(next.ChangeSeq))
+	}
+	return next, cmd
+}
+
+func (m ProgramModel) update(msg tea.Msg) (ProgramModel, tea.Cmd) {
 	/*
 		Group Notes with the same ID to Display order
 	*/
@@ -48,7 +68,33 @@ func (m ProgramModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.IsInit = true
 	}
 	m.StatusText = ""
-	dp := m.UIControl.DisplayOrder
+
+	switch msg := msg.(type) {
+	// Someone else changed the board, pick it up and keep listening
+	case storeChangedMsg:
+		if m.Dirty {
+			// Reloading would throw our edits away, the next save wins instead
+			m.StatusText = "Board changed on disk, your unsaved changes will overwrite it"
+		} else if state, err := m.Store.Load(); err == nil {
+			m.BoardState = state
+			m.History = History{}
+			m.ClampCursor()
+			m.StatusText = "Board reloaded"
+		}
+		return m, waitForStoreChange(m.StoreEvents)
+
+	case autoSaveMsg:
+		// Only the timer of the latest change saves, older ones were debounced away.
+		// Journals are never dirty but still get saved, that's when they compact.
+		_, journal := m.Store.(Journal)
+		if msg.seq == m.ChangeSeq && (m.Dirty || journal) {
+			if err := m.Save(); err != nil {
+				m.StatusText = "Auto-save failed: " + err.Error()
+			}
+		}
+		return m, nil
+	}
+
 	/*
 		Lets think about the algo
 		concern:
@@ -58,6 +104,7 @@ func (m ProgramModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	*/
 
 	if m.IsTextInputShown {
+		var opCmd tea.Cmd
 		switch msg := msg.(type) {
 		case tea.WindowSizeMsg:
 			m.UIControl.TermSize.Height = msg.Height
@@ -67,75 +114,115 @@ func (m ProgramModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case tea.KeyMsg:
 			switch msg.String() {
 			case "enter":
+				m.TextInput.Blur()
+				value := m.TextInput.Value()
+				m.TextInput.SetValue("")
+
 				switch m.Operation {
-				case "ADDNOTE":
-					{
-						m.TextInput.Blur()
-						content := m.TextInput.Value()
-						AddNote(&m, content)
-						m.TextInput.SetValue("")
-						if notes, ok := FindNotesBySectionOrder(m, m.UIControl.SectionCursor); ok {
-							m.UIControl.RowCursor = len(notes) - 1
+				case AddNoteOperation:
+					section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
+					if ok && m.applyOrReport(AddNoteCmd{SectionID: section.ID, Content: value}) {
+						if !m.SelectNote(m.NextID - 1) {
+							m.StatusText = "Note added, it is hidden by the tag filter"
 						}
 					}
-				case "EDITNOTE":
-					{
-						m.TextInput.Blur()
-						content := m.TextInput.Value()
-						note := FindNoteByBothOrder(m, m.UIControl.SectionCursor, m.UIControl.RowCursor)
-						if note != nil {
-							EditNote(note, content)
-							note.DateUpdated = time.Now()
-						}
-						m.TextInput.SetValue("")
+
+				case EditNoteOperation:
+					if note := m.SelectedNote(); note != nil {
+						m.applyOrReport(EditNoteCmd{SectionID: note.SectionID, Order: note.Order, Content: value})
 					}
-				case "ADDSECTION":
-					{
-						m.TextInput.Blur()
-						name := m.TextInput.Value()
-						name = strings.TrimSpace(name)
-						if name == "" {
-							name = "Unnamed Section"
-						}
 
-						maxOrder := -1
-						if len(m.SectionData) > 0 {
-							maxOrder = slices.MaxFunc(m.SectionData, func(a, b Section) int {
-								return a.Order - b.Order
-							}).Order
+				case TagNoteOperation:
+					if note := m.SelectedNote(); note != nil {
+						if m.applyOrReport(TagNoteCmd{SectionID: note.SectionID, Order: note.Order, Tags: parseTags(value)}) {
+							m.ClampCursor()
 						}
-						mockId++
-						m.SectionData = append(m.SectionData, NewSection(name, maxOrder+1, mockId))
-						m.UIControl.SectionCursor = maxOrder + 1
-						m.RepopulateDisplayOrder()
 					}
 
-				case "EDITSECTION":
-					{
-						m.TextInput.Blur()
-						name := m.TextInput.Value()
-						section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
-						if ok {
-							EditSection(section, name)
-						}
+				case DueNoteOperation:
+					note := m.SelectedNote()
+					due, err := parseDue(value, time.Now())
+					if err != nil {
+						m.StatusText = err.Error()
+					} else if note != nil {
+						m.applyOrReport(DueNoteCmd{SectionID: note.SectionID, Order: note.Order, DueDate: due})
+					}
+
+				case SearchOperation:
+					m.UpdateSearch(value)
+
+				case SaveViewOperation:
+					m.SaveView(value)
+
+				case ExportOperation:
+					m.Export(value)
+
+				case AddSectionOperation:
+					name := strings.TrimSpace(value)
+					if name == "" {
+						name = "Unnamed Section"
+					}
+					if m.applyOrReport(AddSectionCmd{ID: m.NewID(), Name: name}) {
+						m.UIControl.SectionCursor = len(m.SectionData) - 1
+						m.UIControl.RowCursor = 0
+					}
 
-						m.TextInput.SetValue("")
-						m.RepopulateDisplayOrder()
+				case EditSectionOperation:
+					if section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor); ok {
+						m.applyOrReport(RenameSectionCmd{SectionID: section.ID, Name: value})
 					}
+
+				case NewBoardOperation:
+					m, opCmd = m.CreateBoard(value)
+
+				case RenameBoardOperation:
+					m, opCmd = m.RenameBoard(value)
 				}
 
 				//Reset to default. ready for new Operation
-				m.Operation = ""
+				m.Operation = NoOperation
 				m.IsTextInputShown = false
 
 			case "esc":
-				m.Operation = ""
+				if m.Operation == SearchOperation {
+					m.UIControl.Search = ""
+				}
+				m.Operation = NoOperation
 				m.IsTextInputShown = false
 				m.TextInput.SetValue("")
 			}
 		}
 		m.TextInput, cmd = m.TextInput.Update(msg)
-		return m, cmd
+		// Search follows every key press, not only enter
+		if m.Operation == SearchOperation && m.TextInput.Value() != m.UIControl.Search {
+			m.UpdateSearch(m.TextInput.Value())
+		}
+		return m, tea.Batch(cmd, opCmd)
+
+	} else if key, ok := msg.(tea.KeyMsg); ok && m.UIControl.IsDialogOpened {
+		// A dialog sits on top of every overlay and gets the keys before them
+		return m.UpdateDialog(key)
+
+	} else if key, ok := msg.(tea.KeyMsg); ok && m.UIControl.Overlay == BoardPickerOverlay {
+		return m.UpdateBoardPicker(key)
+
+	} else if key, ok := msg.(tea.KeyMsg); ok && m.UIControl.Overlay == RecoveryOverlay {
+		return m.UpdateRecovery(key)
+
+	} else if key, ok := msg.(tea.KeyMsg); ok && m.UIControl.Overlay == NoteDetailOverlay {
+		return m.UpdateNoteDetail(key)
+
+	} else if key, ok := msg.(tea.KeyMsg); ok && m.UIControl.Overlay == TagFilterOverlay {
+		return m.UpdateTagPicker(key)
+
+	} else if key, ok := msg.(tea.KeyMsg); ok && m.UIControl.Overlay == ArchiveOverlay {
+		return m.UpdateArchive(key)
+
+	} else if key, ok := msg.(tea.KeyMsg); ok && m.UIControl.Overlay == TrashOverlay {
+		return m.UpdateTrash(key)
+
+	} else if m.UIControl.Overlay == NoteEditorOverlay {
+		return m.UpdateNoteEditor(msg)
 
 	} else {
 		switch msg := msg.(type) {
@@ -145,9 +232,10 @@ func (m ProgramModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Is it a key press?
 		case tea.KeyMsg:
+			dp := m.UIControl.DisplayOrder
 			switch msg.String() {
 			case "ctrl+c", "q":
-				return m, tea.Quit
+				return m.Quit()
 
 			// The "up" and "k" keys move the cursor up
 			case "up", "k":
@@ -169,333 +257,213 @@ func (m ProgramModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "left", "h":
 				if m.UIControl.SectionCursor > 0 {
 					m.UIControl.SectionCursor--
-
-					section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
-					if !ok {
-						return m, nil
-					}
-					notesCount := len(dp[section.ID])
-					if m.UIControl.RowCursor > notesCount-1 {
-						m.UIControl.RowCursor = notesCount - 1
-					}
-					if m.UIControl.RowCursor < 0 {
-						m.UIControl.RowCursor = 0
-					}
+					m.ClampCursor()
 				}
 
 			// The "left" and "h" keys move the cursor right to the next section
 			case "right", "l":
 				if m.UIControl.SectionCursor < len(dp)-1 {
 					m.UIControl.SectionCursor++
-
-					section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
-					if !ok {
-						return m, nil
-					}
-					if notesCount := len(dp[section.ID]); m.UIControl.RowCursor > notesCount-1 {
-						m.UIControl.RowCursor = notesCount - 1
-					}
-					if m.UIControl.RowCursor < 0 {
-						m.UIControl.RowCursor = 0
-					}
+					m.ClampCursor()
 				}
 
-			// The "enter" key and the spacebar (a literal space) toggle
-			// the selected state for the item that the cursor is pointing at.
-			case "enter", " ":
-				section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
-				if !ok {
-					return m, nil
-				}
-
-				sectionNotePtrs, ok := m.UIControl.DisplayOrder[section.ID]
-				if ok {
-					if len(sectionNotePtrs) > 0 {
-						notePtr := sectionNotePtrs[m.UIControl.RowCursor]
-						notePtr.IsChecked = !notePtr.IsChecked
-					}
+			// The spacebar (a literal space) toggles the selected state for
+			// the item that the cursor is pointing at.
+			case " ":
+				if note := m.SelectedNote(); note != nil {
+					m.applyOrReport(ToggleNoteCmd{SectionID: note.SectionID, Order: note.Order})
 				}
 
 			case "a":
-				m.Operation = "ADDNOTE"
-				m.IsTextInputShown = true
-				m.InputPrompt = "What is the content of the note?"
-				m.TextInput.Placeholder = "Type note content here"
-				m.TextInput.SetValue("")
-				m.TextInput, cmd = m.TextInput.Update(nil)
-				m.TextInput.Focus()
-				return m, cmd
+				return m, m.OpenTextInputLimit(AddNoteOperation, "What is the content of the note?", "Type note content here", "", noteCharLimit)
 
 			case "e":
-				m.Operation = "EDITNOTE"
-				m.IsTextInputShown = true
-				m.InputPrompt = "What is the content of the note?"
-				m.TextInput.Placeholder = "Type note content here"
-				note := FindNoteByBothOrder(m, m.UIControl.SectionCursor, m.UIControl.RowCursor)
-				if note != nil {
-					m.TextInput.SetValue(note.Content)
-				} else {
-					m.TextInput.SetValue("")
+				content := ""
+				if note := m.SelectedNote(); note != nil {
+					content = note.Content
 				}
-				m.TextInput, cmd = m.TextInput.Update(nil)
-				m.TextInput.Focus()
-				return m, cmd
+				return m, m.OpenTextInputLimit(EditNoteOperation, "What is the content of the note?", "Type note content here", content, noteCharLimit)
 
 			case "d":
-				section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
-				if !ok {
-					return m, nil
+				if note := m.SelectedNote(); note != nil {
+					if m.applyOrReport(DeleteNoteCmd{SectionID: note.SectionID, Order: note.Order}) && m.UIControl.RowCursor > 0 {
+						m.UIControl.RowCursor--
+					}
 				}
-				if _, ok = m.UIControl.DisplayOrder[section.ID]; ok {
-					sectionIdx := slices.IndexFunc(m.SectionData, func(sec Section) bool {
-						return sec.Order == m.UIControl.SectionCursor
-					})
 
-					if sectionIdx != -1 {
-						sec := m.SectionData[sectionIdx]
+			case "i":
+				return m, m.OpenNoteEditor()
 
-						m.Notes = slices.DeleteFunc(m.Notes, func(n *Note) bool {
-							return n.Order == m.UIControl.RowCursor && n.SectionID == sec.ID
-						})
-
-						if m.UIControl.RowCursor > 0 {
-							m.UIControl.RowCursor--
-						}
-						m.RepopulateDisplayOrder()
-						RecalulateNoteOrder(m.UIControl.DisplayOrder[sec.ID])
+			case "x":
+				if note := m.SelectedNote(); note != nil {
+					if m.applyOrReport(ArchiveNoteCmd{SectionID: note.SectionID, Order: note.Order}) {
+						m.ClampCursor()
 					}
 				}
 
-			case "A":
-				m.Operation = "ADDSECTION"
-				m.IsTextInputShown = true
-				m.InputPrompt = "What is the name of this section?"
-				m.TextInput.Placeholder = "Type the section's name here"
-				m.TextInput.SetValue("")
-				m.TextInput, cmd = m.TextInput.Update(nil)
-				m.TextInput.Focus()
-				return m, cmd
+			case "X":
+				return m.ArchiveChecked()
 
-			case "E":
-				m.Operation = "EDITSECTION"
-				m.IsTextInputShown = true
-				m.InputPrompt = "What is the name of this section?"
-				m.TextInput.Placeholder = "Type the section's name here"
+			case "ctrl+a":
+				m.OpenArchive()
 
-				if section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor); ok {
-					m.TextInput.SetValue(section.Name)
-				} else {
-					m.TextInput.SetValue("")
-				}
-				m.TextInput, cmd = m.TextInput.Update(nil)
-				m.TextInput.Focus()
-				return m, cmd
+			case "enter", "o":
+				m.OpenNoteDetail()
 
-			case "D":
-				{
-					if len(m.SectionData) == 1 {
-						break
-					}
+			case "t":
+				return m, m.OpenTagEditor()
 
-					sectionIdx := slices.IndexFunc(m.SectionData, func(sec Section) bool {
-						return sec.Order == m.UIControl.SectionCursor
-					})
+			case "T":
+				m.OpenTagPicker()
 
-					if sectionIdx != -1 {
-						sec := m.SectionData[sectionIdx]
-						//Delete Notes Data
-						m.Notes = slices.DeleteFunc(m.Notes, func(n *Note) bool {
-							return n.SectionID == sec.ID
-						})
-					}
+			case "w":
+				return m, m.OpenDueEditor()
 
-					//Delete Section Data
-					// m.SectionData = slices.DeleteFunc(m.SectionData, func(sec Section) bool { return sec.Order == m.UIControl.SectionCursor })
-					m.SectionData = slices.Delete(m.SectionData, sectionIdx, sectionIdx+1)
-					//Recalculate Section Order
-					m.RepopulateDisplayOrder()
-					RecalulateSectionOrder(m.SectionData)
+			case "/":
+				return m, m.OpenSearch()
 
-					if m.UIControl.SectionCursor > 0 {
-						m.UIControl.SectionCursor--
-					}
-				}
-			case "ctrl+s":
-				{
-					saveData := map[string]interface{}{
-						"SectionData": m.SectionData,
-						"Notes":       m.Notes,
-					}
-
-					jsonData, err := json.MarshalIndent(saveData, "", "    ")
-					if err != nil {
-						m.Debug = err.Error()
-					}
+			case "v":
+				m.CycleView()
 
-					err = os.WriteFile("./data/save_file.json", jsonData, 0600)
+			case "V":
+				return m, m.OpenViewEditor()
 
-					if err != nil {
-						m.Debug = err.Error()
-					}
-
-					m.StatusText = "Data Saved!"
+			case "n", "N":
+				if m.UIControl.Search == "" {
+					m.StatusText = "Press / to search"
+					break
 				}
-			case "ctrl+r":
-				{
-					m = LoadMockData()
-					m.TextInput = NewTextInputSetting()
+				step := 1
+				if msg.String() == "N" {
+					step = -1
 				}
-			case "alt+up":
-				{
-					if m.UIControl.RowCursor == 0 {
-						break
-					}
-
-					notes, ok := FindNotesBySectionOrder(m, m.UIControl.SectionCursor)
-					if !ok {
-						break
-					}
-
-					curNote := FindNoteByItsOrder(notes, m.UIControl.RowCursor)
-					passNote := FindNoteByItsOrder(notes, m.UIControl.RowCursor-1)
-
-					curNote.Order = curNote.Order - 1
-					passNote.Order = passNote.ID + 1
-					m.UIControl.RowCursor--
+				m.JumpToHit(step)
 
+			case "S":
+				section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
+				if !ok {
+					break
 				}
-			case "alt+down":
-				{
-					notes, ok := FindNotesBySectionOrder(m, m.UIControl.SectionCursor)
-					if !ok {
-						break
-					}
-					if m.UIControl.RowCursor == len(notes)-1 {
-						break
-					}
-
-					curNote := FindNoteByItsOrder(notes, m.UIControl.RowCursor)
-					nextNote := FindNoteByItsOrder(notes, m.UIControl.RowCursor+1)
-
-					curNote.Order++
-					nextNote.Order--
-					m.UIControl.RowCursor++
+				selected := m.SelectedNote()
+				if m.applyOrReport(SortByDueCmd{SectionID: section.ID}) && selected != nil {
+					m.SelectNote(selected.ID)
 				}
-			case "alt+left":
-				{
-					if m.UIControl.SectionCursor == 0 {
-						break
-					}
-
-					currNote := FindNoteByBothOrder(m, m.UIControl.SectionCursor, m.UIControl.RowCursor)
-					if currNote == nil {
-						break
-					}
 
-					section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor-1)
-					if !ok {
-						break
-					}
-					currNote.SectionID = section.ID
-					currNote.DateUpdated = time.Now()
-
-					passSec, ok := FindNotesBySectionOrder(m, m.UIControl.SectionCursor-1)
-					if !ok {
-						break
-					}
-					passSec = append(passSec, currNote)
-					RecalulateNoteOrder(passSec)
-
-					curSec, ok := FindNotesBySectionOrder(m, m.UIControl.SectionCursor)
-					if !ok {
-						break
-					}
-					curSec = slices.DeleteFunc(curSec, func(n *Note) bool {
-						return n.Order == currNote.Order
-					})
-					RecalulateNoteOrder(curSec)
-
-					m.RepopulateDisplayOrder()
-					m.UIControl.SectionCursor--
+			case "A":
+				return m, m.OpenTextInput(AddSectionOperation, "What is the name of this section?", "Type the section's name here", "")
 
+			case "E":
+				name := ""
+				if section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor); ok {
+					name = section.Name
 				}
+				return m, m.OpenTextInput(EditSectionOperation, "What is the name of this section?", "Type the section's name here", name)
 
-			case "alt+right":
-				{
-					if m.UIControl.SectionCursor == len(m.SectionData)-1 {
-						break
-					}
+			case "D":
+				section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
+				if !ok || len(m.SectionData) == 1 {
+					break
+				}
+				id := section.ID
+				return m.Confirm(ConfirmDeleteSection, ConfirmDialog{
+					Message:  fmt.Sprintf("Move section %s and its %s to the trash?", section.Name, plural(len(m.NotesIn(id)), "note")),
+					YesLabel: "delete",
+					Yes: func(m ProgramModel) (ProgramModel, tea.Cmd) {
+						if m.applyOrReport(DeleteSectionCmd{SectionID: id}) && m.UIControl.SectionCursor > 0 {
+							m.UIControl.SectionCursor--
+						}
+						return m, nil
+					},
+				})
 
-					currNote := FindNoteByBothOrder(m, m.UIControl.SectionCursor, m.UIControl.RowCursor)
-					if currNote == nil {
-						break
-					}
+			case "b":
+				m.OpenBoardPicker()
 
-					section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor+1)
-					if !ok {
-						break
-					}
-					currNote.SectionID = section.ID
-					currNote.DateUpdated = time.Now()
+			case "ctrl+t":
+				m.OpenTrash()
 
-					nextSec, ok := FindNotesBySectionOrder(m, m.UIControl.SectionCursor+1)
-					if !ok {
-						break
-					}
-					nextSec = append(nextSec, currNote)
-					RecalulateNoteOrder(nextSec)
+			case "ctrl+e":
+				return m, m.OpenExport()
 
-					curSec, ok := FindNotesBySectionOrder(m, m.UIControl.SectionCursor)
-					if !ok {
-						break
-					}
-					curSec = slices.DeleteFunc(curSec, func(n *Note) bool {
-						return n.Order == currNote.Order
-					})
-					RecalulateNoteOrder(curSec)
-
-					m.UIControl.SectionCursor++
-				}
-			case "alt+shift+left":
+			case "ctrl+s":
 				{
-					if m.UIControl.SectionCursor == 0 {
-						break
-					}
-					currSection, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
-					if !ok {
-						break
-					}
-					passSection, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor-1)
-					if !ok {
+					if err := m.Save(); err != nil {
+						m.StatusText = "Save failed: " + err.Error()
 						break
 					}
 
-					currSection.Order--
-					passSection.Order++
-
-					m.UIControl.SectionCursor--
-
+					m.StatusText = "Data Saved!"
+				}
+			case "u":
+				if !m.Undo() {
+					m.StatusText = "Nothing to undo"
 				}
 
-			case "alt+shift+right":
-				{
-					if m.UIControl.SectionCursor == len(m.SectionData)-1 {
-						break
-					}
+			case "ctrl+r":
+				if !m.Redo() {
+					m.StatusText = "Nothing to redo"
+				}
 
-					currSection, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
-					if !ok {
-						break
-					}
-					nextSection, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor+1)
-					if !ok {
-						break
-					}
+			case "ctrl+g":
+				return m.Confirm(ConfirmReplaceBoard, ConfirmDialog{
+					Message:  "Replace the whole board with mock data? u brings it back.",
+					YesLabel: "replace",
+					Yes: func(m ProgramModel) (ProgramModel, tea.Cmd) {
+						if m.applyOrReport(ReplaceBoardCmd{Board: LoadMockData().BoardState}) {
+							m.UIControl.SectionCursor = 0
+							m.UIControl.RowCursor = 0
+						}
+						return m, nil
+					},
+				})
+
+			case "alt+up", "alt+down":
+				// Swap places with the neighbour on screen, notes hidden by a
+				// filter in between are skipped
+				step := 1
+				if msg.String() == "alt+up" {
+					step = -1
+				}
+				note := m.SelectedNote()
+				if note == nil {
+					break
+				}
+				notes := dp[note.SectionID]
+				next := m.UIControl.RowCursor + step
+				if next < 0 || next >= len(notes) {
+					break
+				}
+				move := MoveNoteCmd{SectionID: note.SectionID, Order: note.Order, ToSectionID: note.SectionID, ToOrder: notes[next].Order}
+				if m.applyOrReport(move) {
+					m.UIControl.RowCursor = next
+				}
 
-					currSection.Order++
-					nextSection.Order--
+			case "alt+left", "alt+right":
+				step := 1
+				if msg.String() == "alt+left" {
+					step = -1
+				}
+				note := m.SelectedNote()
+				target, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor+step)
+				if note == nil || !ok {
+					break
+				}
+				if m.applyOrReport(MoveNoteCmd{SectionID: note.SectionID, Order: note.Order, ToSectionID: target.ID, ToOrder: -1}) {
+					// Follow the note, it went to the bottom of the other section
+					m.SelectNote(note.ID)
+				}
 
-					m.UIControl.SectionCursor++
+			case "alt+shift+left", "alt+shift+right":
+				step := 1
+				if msg.String() == "alt+shift+left" {
+					step = -1
+				}
+				section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
+				if !ok || m.UIControl.SectionCursor+step < 0 || m.UIControl.SectionCursor+step >= len(m.SectionData) {
+					break
+				}
+				if m.applyOrReport(MoveSectionCmd{SectionID: section.ID, ToOrder: section.Order + step}) {
+					m.UIControl.SectionCursor += step
 				}
 
 			default:
@@ -510,11 +478,93 @@ func (m ProgramModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// applyOrReport applies cmd and puts the error in the status line if it fails
+func (m *ProgramModel) applyOrReport(cmd Command) bool {
+	if err := m.Apply(cmd); err != nil {
+		m.StatusText = err.Error()
+		return false
+	}
+	return true
+}
+
+// OpenTextInput shows the text input for op, prefilled with value
+func (m *ProgramModel) OpenTextInput(op InputOperation, prompt string, placeholder string, value string) tea.Cmd {
+	return m.OpenTextInputLimit(op, prompt, placeholder, value, NewTextInputSetting().CharLimit)
+}
+
+// OpenTextInputLimit is OpenTextInput taking up to limit characters. The limit
+// never cuts value short, a longer value raises it.
+func (m *ProgramModel) OpenTextInputLimit(op InputOperation, prompt string, placeholder string, value string, limit int) tea.Cmd {
+	var cmd tea.Cmd
+	m.Operation = op
+	m.IsTextInputShown = true
+	m.InputPrompt = prompt
+	m.TextInput.CharLimit = max(limit, utf8.RuneCountInString(value))
+	m.TextInput.Placeholder = placeholder
+	m.TextInput.SetValue(value)
+	m.TextInput, cmd = m.TextInput.Update(nil)
+	m.TextInput.Focus()
+	return cmd
+}
+
 func (m ProgramModel) View() string {
 
+	allText := ""
+	switch m.UIControl.Overlay {
+	case BoardPickerOverlay:
+		allText = m.BoardPickerView()
+	case RecoveryOverlay:
+		allText = m.RecoveryView()
+	case NoteEditorOverlay:
+		allText = m.NoteEditorView()
+	case NoteDetailOverlay:
+		allText = m.NoteDetailView()
+	case TagFilterOverlay:
+		allText = m.TagPickerView()
+	case TrashOverlay:
+		allText = m.TrashView()
+	case ArchiveOverlay:
+		allText = m.ArchiveView()
+	default:
+		allText = m.BoardView()
+	}
+	if m.UIControl.IsDialogOpened {
+		allText = m.DialogView()
+	}
+
+	// The footer
+
+	if m.IsTextInputShown {
+		allText += fmt.Sprintf(
+			"\n%s\n\n%s\n\n%s",
+			m.InputPrompt,
+			m.TextInput.View(),
+			"(esc to cancel)\n",
+		)
+	} else {
+		allText += "\nPress q to quit, b to switch boards.\n"
+
+	}
+
+	allText += m.StatusText
+
+	// DEBUG
+	// allText += spew.Sdump(m.SectionData)
+	allText += m.Debug
+
+	// Send the UI for rendering
+
+	return systemStyle.Width(m.UIControl.TermSize.Width - 3).Height(m.UIControl.TermSize.Height - 5).Render(allText)
+
+}
+
+// BoardView renders the sections and their notes side by side
+func (m ProgramModel) BoardView() string {
+
 	// The header
 	allText := ""
-	sectionLen := len(m.SectionData)
+	layout := m.Layout()
+	now := time.Now()
 
 	dpo := m.UIControl.DisplayOrder
 	sectionIDs := maps.Keys(dpo)
@@ -538,6 +588,11 @@ func (m ProgramModel) View() string {
 		return a.Order - b.Order
 	})
 
+	// Only the sections that fit, scrolled to follow the cursor
+	hiddenLeft := clamp(0, m.UIControl.SectionOffset, len(sectionList))
+	hiddenRight := max(len(sectionList)-hiddenLeft-layout.Columns, 0)
+	sectionList = sectionList[hiddenLeft : len(sectionList)-hiddenRight]
+
 	// Iterate over our sections
 	for loopCnt, section := range sectionList {
 		notesInSection := dpo[section.ID]
@@ -555,23 +610,31 @@ func (m ProgramModel) View() string {
 			sectionText = sectionHeaderStyle.Render(section.Name)
 		}
 
-		sectionText += "\n\n"
-		sortedNotes := slices.Clone(notesInSection)
-		slices.SortFunc(sortedNotes, func(a, b *Note) int {
-			return a.Order - b.Order
-		})
+		sectionText += "\n"
 
-		// Iterate over our sortedNotes in the section
-		for _, note := range sortedNotes {
+		// Only the cards that fit, DisplayOrder is already sorted
+		offset := clamp(0, m.UIControl.RowOffsets[section.ID], len(notesInSection))
+		end := min(offset+layout.Rows, len(notesInSection))
+		sectionText += scrollHintStyle.Render(scrollHint("▲", offset)) + "\n"
+
+		// Iterate over the visible notes in the section
+		for i := offset; i < end; i++ {
+			note := notesInSection[i]
 			// Is the cursor pointing at this item?
 
 			style := cardStyle
-			style = style.Width((m.UIControl.TermSize.Width - 5) / (sectionLen + 3))
+			switch note.DueStatus(now) {
+			case Overdue:
+				style = overdueCardStyle
+			case DueSoon:
+				style = dueSoonCardStyle
+			}
+			style = style.Width(layout.CardWidth)
 			// cursor := " " // no cursor
-			if m.UIControl.RowCursor == note.Order && m.UIControl.SectionCursor == section.Order {
+			if m.UIControl.RowCursor == i && m.UIControl.SectionCursor == section.Order {
 				// cursor = ">" // cursor!
 
-				style = style.BorderStyle(lg.DoubleBorder()).Background(lg.Color(CardBackgroudColor))
+				style = style.BorderStyle(lg.DoubleBorder())
 			}
 
 			// Is this item selected?
@@ -582,11 +645,21 @@ func (m ProgramModel) View() string {
 			}
 
 			// tmpS := fmt.Sprintf(" %s[%s] %s", cursor, checked, note.Content)
-			tmpS := note.Content
+			title := note.Content
+			if positions, ok := m.SearchMatch(note); ok {
+				title = highlight(title, positions, lg.NewStyle().Foreground(style.GetForeground()).Background(style.GetBackground()))
+			}
+			tmpS := ""
+			if footer := cardFooter(note, layout.CardWidth-4, now); footer != "" {
+				tmpS = notePreview(note, title, layout.CardWidth-4, 2) + "\n" + footer
+			} else {
+				tmpS = notePreview(note, title, layout.CardWidth-4, 3)
+			}
 			tmpS = style.Render(tmpS)
 			sectionText += tmpS
 			sectionText += "\n"
 		}
+		sectionText += scrollHintStyle.Render(scrollHint("▼", len(notesInSection)-end))
 
 		allText = lg.JoinHorizontal(lg.Top, allText, sectionText)
 		if loopCnt < len(sectionList)-1 {
@@ -594,36 +667,53 @@ func (m ProgramModel) View() string {
 		}
 	}
 
-	// The footer
-
-	if m.IsTextInputShown {
-		allText += fmt.Sprintf(
-			"\n%s\n\n%s\n\n%s",
-			m.InputPrompt,
-			m.TextInput.View(),
-			"(esc to cancel)\n",
+	title := boardTitleStyle.Render(m.BoardName)
+	if m.Dirty {
+		title += dirtyMarkStyle.Render(" ● unsaved")
+	}
+	title += m.viewTitle()
+	title += m.tagFilterTitle()
+	if hiddenLeft > 0 || hiddenRight > 0 {
+		title += "  " + scrollHintStyle.Render(
+			scrollHint("◀", hiddenLeft)+"  "+scrollHint("▶", hiddenRight),
 		)
-	} else {
-		allText += "\nPress q to quit.\n"
-
 	}
+	return title + "\n\n" + allText
+}
 
-	allText += m.StatusText
-
-	// DEBUG
-	// allText += spew.Sdump(m.SectionData)
-	allText += m.Debug
-
-	// Send the UI for rendering
+func main() {
+	cfg, err := LoadConfig(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	} else if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
 
-	return systemStyle.Width(m.UIControl.TermSize.Width - 3).Height(m.UIControl.TermSize.Height - 5).Render(allText)
+	if len(cfg.Args) > 0 {
+		os.Exit(runSubcommand(cfg, cfg.Args, os.Stdout, os.Stderr))
+	}
 
-}
+	boards := NewBoardRegistry(cfg.BoardDir, cfg.Format, cfg.Backups)
+	boards.CompactEvery = cfg.CompactEvery
 
-func main() {
-	p := tea.NewProgram(initialModel(), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Alas, there's been an error: %v", err)
+	model, err := initialModel(boards, cfg.BoardName)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Could not open board %s: %v\n", cfg.BoardName, err)
+		os.Exit(1)
+	}
+	model.PurgeAfter = cfg.PurgeAfter
+	model.NoConfirm = cfg.NoConfirm
+	model.ArchiveAfter = cfg.ArchiveAfter
+	model.AutoArchive()
+	model.AutoPurge()
+
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	final, err := p.Run()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Alas, there's been an error: %v\n", err)
 		os.Exit(1)
 	}
+	// The board might have been switched, close whatever store is open now
+	final.(ProgramModel).Store.Close()
 }
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+var keyTypes = map[string]tea.KeyType{
+	"enter":  tea.KeyEnter,
+	"esc":    tea.KeyEsc,
+	" ":      tea.KeySpace,
+	"ctrl+a": tea.KeyCtrlA,
+	"ctrl+c": tea.KeyCtrlC,
+	"ctrl+e": tea.KeyCtrlE,
+	"ctrl+r": tea.KeyCtrlR,
+	"ctrl+s": tea.KeyCtrlS,
+	"ctrl+t": tea.KeyCtrlT,
+}
+
+func keyMsg(k string) tea.KeyMsg {
+	if t, ok := keyTypes[k]; ok {
+		return tea.KeyMsg{Type: t}
+	}
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)}
+}
+
+// press sends every key to the model in turn, like someone typing them
+func press(m ProgramModel, keys ...string) ProgramModel {
+	for _, k := range keys {
+		next, _ := m.Update(keyMsg(k))
+		m = next.(ProgramModel)
+	}
+	return m
+}
+
+// typeText types s into the text input one rune at a time
+func typeText(m ProgramModel, s string) ProgramModel {
+	for _, r := range s {
+		next, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		m = next.(ProgramModel)
+	}
+	return m
+}
+
+// play presses keys like press, steps starting with + are typed with typeText instead
+func play(m ProgramModel, steps ...string) ProgramModel {
+	for _, step := range steps {
+		if text, ok := strings.CutPrefix(step, "+"); ok {
+			m = typeText(m, text)
+		} else {
+			m = press(m, step)
+		}
+	}
+	return m
+}
+
+// testModel opens a blank board called "board" in a temporary directory
+func testModel(t *testing.T) ProgramModel {
+	t.Helper()
+	m, err := initialModel(NewBoardRegistry(t.TempDir(), JSONFormat, 0), "board")
+	if err != nil {
+		t.Fatal(err)
+	}
+	next, _ := m.Update(tea.WindowSizeMsg{Width: 160, Height: 50})
+	return next.(ProgramModel)
+}
+
+// titles lists the titles of the notes in the section at order
+func titles(m ProgramModel, order int) []string {
+	notes, _ := FindNotesBySectionOrder(m, order)
+	names := []string{}
+	for _, n := range notes {
+		names = append(names, n.Content)
+	}
+	return names
+}
@@ -1,86 +1,119 @@
 package main
 
 import (
-	"encoding/json"
-	"os"
+	"errors"
 	"slices"
 	"strconv"
 	"time"
 
+	"github.com/charmbracelet/bubbles/textarea"
 	"github.com/charmbracelet/bubbles/textinput"
 )
 
 type ProgramModel struct {
+	BoardState
 	UIControl        UIControl
-	Notes            []*Note
-	SectionData      []Section
+	BoardName        string          // Name of the board in Boards that is on screen
+	Boards           BoardRegistry   // Every board the user has
+	Store            Store           // Where the board is loaded from and saved to
+	StoreEvents      <-chan struct{} // Outside changes reported by Store.Watch
+	Dirty            bool            // Board has changes that are not saved yet
+	ChangeSeq        int             // Bumped on every change, used to debounce auto-save
+	History          History         // Undo and redo stacks
+	PurgeAfter       int             // Days deleted things stay in the trash, 0 keeps them
+	NoConfirm        []ConfirmAction // Actions that run without asking first
+	ArchiveAfter     int             // Days a note stays checked before it is archived, 0 never
 	IsInit           bool
 	IsTextInputShown bool
 	TextInput        textinput.Model
+	Editor           textarea.Model // Multi-line editor for note descriptions
 	InputPrompt      string
-	Operation        string // Might change to enum
+	Operation        InputOperation // What the text input is asking for
 	Debug            string
 	StatusText       string
 }
 
-func initialModel() ProgramModel {
+// InputOperation tells what to do with the text input's value on enter
+type InputOperation int
+
+const (
+	NoOperation InputOperation = iota
+	AddNoteOperation
+	EditNoteOperation
+	AddSectionOperation
+	EditSectionOperation
+	NewBoardOperation
+	RenameBoardOperation
+	TagNoteOperation
+	DueNoteOperation
+	SearchOperation
+	SaveViewOperation
+	ExportOperation
+)
 
-	model, err := LoadProgramStateFromJson()
-	if os.IsNotExist(err) {
-		LoadBlankProgramState()
-	} else if err != nil {
-		model = LoadMockData()
-	}
-	ti := NewTextInputSetting()
+func initialModel(boards BoardRegistry, name string) (ProgramModel, error) {
 
-	model.TextInput = ti
-	return model
-}
+	// A board that was never saved starts blank. It gets written on the first save.
+	store := boards.Store(name)
+	state, err := openBoard(store)
+	model := ProgramModel{BoardState: state}
 
-func LoadProgramStateFromJson() (ProgramModel, error) {
-	jsonData, err := os.ReadFile("./data/save_file.json")
-	if err != nil {
-		return ProgramModel{}, err
-	}
-	type StateDTO struct {
-		SectionData []Section
-		Notes       []*Note
-	}
-
-	var dto StateDTO
-	if err := json.Unmarshal(jsonData, &dto); err != nil {
+	var corrupt *CorruptBoardError
+	if errors.As(err, &corrupt) {
+		// Offer the newest backup instead of starting over and saving on top of the broken file
+		restorer, ok := store.(BackupRestorer)
+		if !ok {
+			return ProgramModel{}, err
+		}
+		backup, ok := restorer.LatestValidBackup()
+		if !ok {
+			return ProgramModel{}, err
+		}
+		model.UIControl.Overlay = RecoveryOverlay
+		model.UIControl.RecoveryBackup = backup
+		model.UIControl.RecoveryReason = corrupt.Err.Error()
+	} else if err != nil {
 		return ProgramModel{}, err
 	}
+	ti := NewTextInputSetting()
 
-	return ProgramModel{Notes: dto.Notes, SectionData: dto.SectionData}, nil
+	model.TextInput = ti
+	model.Editor = NewTextAreaSetting()
+	model.BoardName = name
+	model.Boards = boards
+	model.Store = store
+	model.StoreEvents, _ = store.Watch()
+	return model, nil
 }
 
 func LoadMockData() ProgramModel {
 	mockNotes := []*Note{} // Create a slice with capacity for 4 items
 	for i := range 4 {
-		mockNotes = append(mockNotes, NewNote("test"+strconv.Itoa(i), i, 0))
+		mockNotes = append(mockNotes, NewNote("test"+strconv.Itoa(i), i, 0, len(mockNotes)+1))
 	}
 	for i := range 2 {
-		mockNotes = append(mockNotes, NewNote("test"+strconv.Itoa(i), i, 1))
+		mockNotes = append(mockNotes, NewNote("test"+strconv.Itoa(i), i, 1, len(mockNotes)+1))
 	}
 
-	return ProgramModel{
+	return ProgramModel{BoardState: BoardState{
 		Notes: mockNotes,
 		SectionData: []Section{
 			{ID: 0, Order: 0, Name: "Uncategorized"},
 			{ID: 1, Order: 1, Name: "Inbox"},
 		},
-	}
+		NextID: len(mockNotes) + 1,
+	}}
 }
 
 func LoadBlankProgramState() ProgramModel {
 
-	return ProgramModel{
+	return ProgramModel{BoardState: BoardState{
 		Notes: []*Note{},
 		SectionData: []Section{
 			{ID: 0, Order: 0, Name: "Inbox"},
 		},
-	}
+		NextID: 1,
+	}}
 }
 
 func (m *ProgramModel) RepopulateDisplayOrder() {
@@ -90,25 +123,101 @@ func (m *ProgramModel) RepopulateDisplayOrder() {
 	for _, section := range m.SectionData {
 		dp[section.ID] = make([]*Note, 0)
 	}
+	shows := m.noteFilter()
 	for _, notePtr := range m.Notes {
+		// Deleted notes and the notes of deleted sections are in the trash,
+		// archived ones in the archive
+		if _, ok := dp[notePtr.SectionID]; !ok || !notePtr.IsActive() || !shows(notePtr) {
+			continue
+		}
 		dp[notePtr.SectionID] = append(dp[notePtr.SectionID], notePtr)
 	}
+	for _, notes := range dp {
+		slices.SortFunc(notes, func(a, b *Note) int {
+			return a.Order - b.Order
+		})
+	}
+}
+
+// SelectedNote returns the note under the cursor, nil when the section is empty.
+// RowCursor counts the notes on screen, which skips the ones filtered out.
+func (m ProgramModel) SelectedNote() *Note {
+	notes, _ := FindNotesBySectionOrder(m, m.UIControl.SectionCursor)
+	if m.UIControl.RowCursor < 0 || m.UIControl.RowCursor >= len(notes) {
+		return nil
+	}
+	return notes[m.UIControl.RowCursor]
+}
+
+// SelectNote puts the cursor on the note with id, if it is on screen
+func (m *ProgramModel) SelectNote(id int) bool {
+	m.RepopulateDisplayOrder()
+	for _, section := range m.SectionData {
+		idx := slices.IndexFunc(m.UIControl.DisplayOrder[section.ID], func(n *Note) bool { return n.ID == id })
+		if idx != -1 {
+			m.UIControl.SectionCursor = section.Order
+			m.UIControl.RowCursor = idx
+			return true
+		}
+	}
+	return false
+}
+
+// ClampCursor keeps both cursors inside the board after it was replaced from outside
+func (m *ProgramModel) ClampCursor() {
+	m.RepopulateDisplayOrder()
+	m.UIControl.SectionCursor = clamp(0, m.UIControl.SectionCursor, len(m.SectionData)-1)
+
+	notes, _ := FindNotesBySectionOrder(*m, m.UIControl.SectionCursor)
+	m.UIControl.RowCursor = clamp(0, m.UIControl.RowCursor, len(notes)-1)
+}
+
+// CloneBoardState deep copies the board so the copy shares no Note with the original
+func CloneBoardState(state BoardState) BoardState {
+	clone := BoardState{
+		SectionData: slices.Clone(state.SectionData),
+		Notes:       make([]*Note, 0, len(state.Notes)),
+		NextID:      state.NextID,
+		Views:       slices.Clone(state.Views),
+
+		DeletedSections: slices.Clone(state.DeletedSections),
+	}
+	for _, n := range state.Notes {
+		note := *n
+		note.Tags = slices.Clone(n.Tags)
+		clone.Notes = append(clone.Notes, &note)
+	}
+	return clone
 }
 
 // Notes
 type Note struct {
 	ID          int       // Database ID, unique for each Note
 	Order       int       // Display order of the note
-	Content     string    // The content of the note
+	Content     string    // The title of the note, the one line shown on the card
+	Description string    // Longer multi-line text under the title
+	Tags        []string  // Labels shown as colored chips, see tags.go
+	DueDate     time.Time // Deadline, the zero time when there is none. See due.go
 	SectionID   int       // Pointer to the parent Section
 	DateUpdated time.Time // Timestamp when the note was last updated
 	DateCreated time.Time // Timestamp when the note was created
 	IsChecked   bool      // Is the note completed/checked?
-	IsDeleted   bool      // Flag for soft deletion
+	DateChecked time.Time // When the note was last checked, zero while unchecked
+	IsDeleted   bool      // Flag for soft deletion, the note is in the trash
+	DateDeleted time.Time // When the note went into the trash
+
+	IsArchived   bool      // Done and put away, only shown in the archive browser
+	DateArchived time.Time // When the note was archived
+}
+
+// IsActive tells whether the note belongs on the board, not in the trash or the archive
+func (n *Note) IsActive() bool {
+	return !n.IsDeleted && !n.IsArchived
 }
 
-func NewNote(content string, order int, sectionId int) *Note {
+func NewNote(content string, order int, sectionId int, id int) *Note {
 	return &Note{
+		ID:          id,
 		Content:     content,
 		DateUpdated: time.Now(),
 		DateCreated: time.Now(),
@@ -126,15 +235,45 @@ func NewSection(content string, order int, sectionId int) Section {
 }
 
 type Section struct {
-	ID    int    // Unique identifier for the Section
-	Order int    // Display order
-	Name  string // Section name
+	ID          int       // Unique identifier for the Section
+	Order       int       // Display order
+	Name        string    // Section name
+	DateDeleted time.Time // When the section went into the trash, see BoardState.DeletedSections
 }
 
+// Overlay is a pane drawn instead of the board, it gets the key presses while shown
+type Overlay int
+
+const (
+	NoOverlay Overlay = iota
+	BoardPickerOverlay
+	RecoveryOverlay
+	NoteEditorOverlay
+	NoteDetailOverlay
+	TagFilterOverlay
+	TrashOverlay
+	ArchiveOverlay
+)
+
 type UIControl struct {
-	IsDialogOpened bool            // Tracks if a dialog is open
+	Overlay        Overlay         // Which overlay is on screen, if any
+	BoardPicker    BoardPicker     // State of the board switcher
+	RecoveryBackup string          // Backup offered when the board file is corrupt
+	RecoveryReason string          // Why the board file could not be read
+	EditingNoteID  int             // Note whose description is in the editor
+	EditorReturn   Overlay         // Overlay to go back to when the editor closes
+	TagFilter      []string        // Only notes with all of these tags are shown
+	TagPicker      TagPicker       // State of the tag filter overlay
+	Search         string          // Query of the search, matches are highlighted
+	ViewName       string          // Saved view the board is shown through, empty for none
+	TrashCursor    int             // Item of the trash view under the cursor
+	ArchiveCursor  int             // Note of the archive browser under the cursor
+	IsDialogOpened bool            // A ConfirmDialog is open, it gets the key presses
+	Dialog         ConfirmDialog   // The open dialog, see dialog.go
 	LastUIBuffer   string          // Stores the last state or buffer for UI
 	DisplayOrder   map[int][]*Note // Map SectionIDs to corresponding Notes
+	SectionOffset  int             // How many sections are scrolled off to the left
+	RowOffsets     map[int]int     // Per SectionID, how many notes are scrolled off the top
 	RowCursor      int             // which to-do list item our cursor is pointing at in a section
 	SectionCursor  int             // which column(Section) our cursor is pointing at
 	TermSize       struct {        // terminal size. currently use for fullscreen
@@ -150,7 +289,7 @@ func FindNoteByBothOrder(m ProgramModel, sectionOrder int, noteOrder int) *Note
 	}
 
 	if sectionNotePtrs, ok := m.UIControl.DisplayOrder[section.ID]; ok {
-		if note := FindNoteByItsOrder(sectionNotePtrs, m.UIControl.RowCursor); note != nil {
+		if note := FindNoteByItsOrder(sectionNotePtrs, noteOrder); note != nil {
 			return note
 		}
 	}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// OpenNoteDetail shows every field of the note under the cursor
+func (m *ProgramModel) OpenNoteDetail() {
+	if m.SelectedNote() == nil {
+		return
+	}
+	m.UIControl.Overlay = NoteDetailOverlay
+}
+
+// UpdateNoteDetail handles the keys of the detail pane. The pane always shows
+// the selected note, so the edit keys reuse what they do on the board.
+func (m ProgramModel) UpdateNoteDetail(msg tea.KeyMsg) (ProgramModel, tea.Cmd) {
+	note := m.SelectedNote()
+	if note == nil {
+		m.UIControl.Overlay = NoOverlay
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "esc", "o", "q", "enter":
+		m.UIControl.Overlay = NoOverlay
+
+	case "e":
+		return m, m.OpenTextInputLimit(EditNoteOperation, "What is the content of the note?", "Type note content here", note.Content, noteCharLimit)
+
+	case "i":
+		return m, m.OpenNoteEditor()
+
+	case "t":
+		return m, m.OpenTagEditor()
+
+	case "w":
+		return m, m.OpenDueEditor()
+
+	case " ":
+		m.applyOrReport(ToggleNoteCmd{SectionID: note.SectionID, Order: note.Order})
+
+	case "x":
+		if m.applyOrReport(ArchiveNoteCmd{SectionID: note.SectionID, Order: note.Order}) {
+			m.UIControl.Overlay = NoOverlay
+			m.ClampCursor()
+		}
+
+	case "d":
+		if m.applyOrReport(DeleteNoteCmd{SectionID: note.SectionID, Order: note.Order}) {
+			m.UIControl.Overlay = NoOverlay
+			m.ClampCursor()
+		}
+
+	case "u":
+		if !m.Undo() {
+			m.StatusText = "Nothing to undo"
+		}
+	}
+	return m, nil
+}
+
+func (m ProgramModel) NoteDetailView() string {
+	note := m.SelectedNote()
+	if note == nil {
+		return ""
+	}
+	now := time.Now()
+	width := clamp(30, m.UIControl.TermSize.Width-12, 80)
+
+	status := "open"
+	if note.IsChecked {
+		status = "done"
+		if !note.DateChecked.IsZero() {
+			status += ", checked " + relativeTime(note.DateChecked, now)
+		}
+	}
+	section, _ := m.SectionByID(note.SectionID)
+	due := "none"
+	if !note.DueDate.IsZero() {
+		due = fmt.Sprintf("%s (%s)", dueBadge(note.DueDate, now), note.DueDate.Format("Mon 2 Jan 2006"))
+	}
+	tags := "none"
+	if len(note.Tags) > 0 {
+		tags = tagChips(note.Tags, width)
+	}
+
+	fields := [][2]string{
+		{"Section", section.Name},
+		{"Status", status},
+		{"Tags", tags},
+		{"Due", due},
+		{"Created", fullTime(note.DateCreated, now)},
+		{"Updated", fullTime(note.DateUpdated, now)},
+		{"ID", fmt.Sprint(note.ID)},
+	}
+
+	text := boardPickerTitleStyle.Render(note.Content) + "\n\n"
+	for _, f := range fields {
+		text += fmt.Sprintf("%-9s %s\n", f[0]+":", f[1])
+	}
+
+	description := strings.TrimSpace(note.Description)
+	if description == "" {
+		description = scrollHintStyle.Render("No description")
+	}
+	text += "\n" + description + "\n\n"
+	text += "e: edit title  i: edit description  t: tags  w: due date  space: toggle  x: archive  d: delete  u: undo  esc: close"
+
+	return dialogStyle.Width(width).Render(text)
+}
+
+// relativeTime says how long ago t was, e.g. "3 hours ago"
+func relativeTime(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	d := now.Sub(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return plural(int(d/time.Minute), "minute") + " ago"
+	case d < 24*time.Hour:
+		return plural(int(d/time.Hour), "hour") + " ago"
+	case d < 30*24*time.Hour:
+		return plural(int(d/(24*time.Hour)), "day") + " ago"
+	case d < 365*24*time.Hour:
+		return plural(int(d/(30*24*time.Hour)), "month") + " ago"
+	}
+	return plural(int(d/(365*24*time.Hour)), "year") + " ago"
+}
+
+// fullTime is the relative time followed by the exact one
+func fullTime(t time.Time, now time.Time) string {
+	if t.IsZero() {
+		return "unknown"
+	}
+	return fmt.Sprintf("%s (%s)", relativeTime(t, now), t.Format("2 Jan 2006 15:04"))
+}
+
+func plural(n int, unit string) string {
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
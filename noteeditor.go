@@ -0,0 +1,164 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func NewTextAreaSetting() textarea.Model {
+	ta := textarea.New()
+	ta.CharLimit = 0
+	ta.MaxHeight = 0
+	ta.ShowLineNumbers = false
+	ta.Placeholder = "Describe the note here"
+	ta.SetWidth(60)
+	ta.SetHeight(12)
+
+	return ta
+}
+
+// editorFinishedMsg comes back when $EDITOR exits
+type editorFinishedMsg struct {
+	path string
+	err  error
+}
+
+// OpenNoteEditor shows the description editor for the note under the cursor
+func (m *ProgramModel) OpenNoteEditor() tea.Cmd {
+	note := m.SelectedNote()
+	if note == nil {
+		return nil
+	}
+	m.UIControl.EditorReturn = m.UIControl.Overlay
+	m.UIControl.Overlay = NoteEditorOverlay
+	m.UIControl.EditingNoteID = note.ID
+	m.Editor.SetWidth(clamp(20, m.UIControl.TermSize.Width-12, 80))
+	m.Editor.SetHeight(clamp(3, m.UIControl.TermSize.Height-20, 20))
+	m.Editor.SetValue(note.Description)
+	return m.Editor.Focus()
+}
+
+func (m *ProgramModel) closeNoteEditor() {
+	m.Editor.Blur()
+	m.Editor.SetValue("")
+	m.UIControl.Overlay = m.UIControl.EditorReturn
+}
+
+func (m ProgramModel) UpdateNoteEditor(msg tea.Msg) (ProgramModel, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.UIControl.TermSize.Height = msg.Height
+		m.UIControl.TermSize.Width = msg.Width
+
+	case editorFinishedMsg:
+		defer os.Remove(msg.path)
+		if msg.err != nil {
+			m.StatusText = "Editor failed: " + msg.err.Error()
+			return m, nil
+		}
+		content, err := os.ReadFile(msg.path)
+		if err != nil {
+			m.StatusText = "Could not read the edited note: " + err.Error()
+			return m, nil
+		}
+		m.Editor.SetValue(strings.TrimRight(string(content), "\n"))
+		return m, nil
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			m.closeNoteEditor()
+			return m, nil
+
+		case "ctrl+s":
+			note := m.NoteByID(m.UIControl.EditingNoteID)
+			if note != nil {
+				m.applyOrReport(DescribeNoteCmd{SectionID: note.SectionID, Order: note.Order, Description: m.Editor.Value()})
+			}
+			m.closeNoteEditor()
+			return m, nil
+
+		case "ctrl+e":
+			return m, m.openExternalEditor()
+		}
+	}
+
+	m.Editor, cmd = m.Editor.Update(msg)
+	return m, cmd
+}
+
+// openExternalEditor hands the description to $EDITOR (or $VISUAL, or vi) and
+// puts the result back into the editor overlay when it exits.
+func (m *ProgramModel) openExternalEditor() tea.Cmd {
+	f, err := os.CreateTemp("", "kagoban-note-*.md")
+	if err != nil {
+		m.StatusText = "Could not start the editor: " + err.Error()
+		return nil
+	}
+	_, err = f.WriteString(m.Editor.Value())
+	f.Close()
+	if err != nil {
+		os.Remove(f.Name())
+		m.StatusText = "Could not start the editor: " + err.Error()
+		return nil
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	// $EDITOR may carry flags, e.g. "code --wait"
+	args := append(strings.Fields(editor), f.Name())
+
+	path := f.Name()
+	return tea.ExecProcess(exec.Command(args[0], args[1:]...), func(err error) tea.Msg {
+		return editorFinishedMsg{path: path, err: err}
+	})
+}
+
+func (m ProgramModel) NoteEditorView() string {
+	title := ""
+	if note := m.NoteByID(m.UIControl.EditingNoteID); note != nil {
+		title = note.Content
+	}
+	return dialogStyle.Render(
+		boardPickerTitleStyle.Render(title) + "\n\n" +
+			m.Editor.View() + "\n\n" +
+			"ctrl+s: save  ctrl+e: open in $EDITOR  esc: cancel",
+	)
+}
+
+// notePreview is the title of a card followed by the start of its description,
+// cut to fit lines lines of width columns. title is the note's Content, maybe
+// with search matches highlighted.
+func notePreview(note *Note, title string, width int, lines int) string {
+	text := title
+	if note.Description == "" || lines <= 1 {
+		return text
+	}
+
+	body := strings.Split(strings.TrimSpace(note.Description), "\n")
+	preview := []string{}
+	for i, line := range body {
+		if i == lines-1 {
+			break
+		}
+		if i == lines-2 && len(body) > lines-1 {
+			// More to come than fits, say so on the last line shown
+			line = truncate(line, width-1) + "…"
+		} else {
+			line = truncate(line, width)
+		}
+		preview = append(preview, line)
+	}
+	return text + "\n" + strings.Join(preview, "\n")
+}
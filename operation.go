@@ -1,61 +1,541 @@
 package main
 
-import "slices"
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
 
-func AddNote(m *ProgramModel, content string) bool {
-	section, ok := FindSectionDataByOrder(m.SectionData, m.UIControl.SectionCursor)
-	if !ok {
-		return false
-	}
-	sectionNotePtrs, ok := m.UIControl.DisplayOrder[section.ID]
-	if ok {
-		maxOrder := -1
-		if len(sectionNotePtrs) > 0 {
-			maxOrder = slices.MaxFunc(sectionNotePtrs, func(a, b *Note) int {
-				return a.Order - b.Order
-			}).Order
-		} else {
-			//Hijack this control flow to fix cursor after add notes in an empty section
-			m.UIControl.RowCursor = 0
+/*
+Every change to a board is a Command applied by BoardState.Apply. Commands only
+carry plain data so they can be replayed, logged, undone and built outside of
+Bubble Tea (CLI, scripts, tests). Notes are addressed by their section and
+their Order inside it, which is what the cursor points at.
+*/
+
+var (
+	ErrNoSuchNote    = errors.New("no such note")
+	ErrNoSuchSection = errors.New("no such section")
+	ErrLastSection   = errors.New("can't delete the last section")
+	ErrNoSuchView    = errors.New("no such view")
+
+	ErrNothingToImport = errors.New("nothing to import")
+)
+
+type Command interface {
+	// Kind names the command, e.g. "AddNote". Used when commands are written down.
+	Kind() string
+}
+
+// AddNoteCmd appends a new note at the bottom of a section. The note's ID
+// comes from the board's counter, which replays the same way every time.
+type AddNoteCmd struct {
+	SectionID int
+	Content   string
+}
+
+type EditNoteCmd struct {
+	SectionID int
+	Order     int
+	Content   string
+}
+
+// DescribeNoteCmd replaces the multi-line description under the note's title
+type DescribeNoteCmd struct {
+	SectionID   int
+	Order       int
+	Description string
+}
+
+// TagNoteCmd replaces the tags of a note
+type TagNoteCmd struct {
+	SectionID int
+	Order     int
+	Tags      []string
+}
+
+// DueNoteCmd sets the due date of a note, the zero time removes it
+type DueNoteCmd struct {
+	SectionID int
+	Order     int
+	DueDate   time.Time
+}
+
+type ToggleNoteCmd struct {
+	SectionID int
+	Order     int
+}
+
+// DeleteNoteCmd moves a note to the trash, see trash.go for getting it back
+type DeleteNoteCmd struct {
+	SectionID int
+	Order     int
+}
+
+// MoveNoteCmd moves a note to ToOrder in ToSectionID, which may be the section
+// it is already in. A ToOrder past the end (or -1) puts it at the bottom.
+type MoveNoteCmd struct {
+	SectionID   int
+	Order       int
+	ToSectionID int
+	ToOrder     int
+}
+
+// AddSectionCmd appends a new section on the right. The ID is picked by the
+// caller with BoardState.NewID so replaying the command creates the same section again.
+type AddSectionCmd struct {
+	ID   int
+	Name string
+}
+
+type RenameSectionCmd struct {
+	SectionID int
+	Name      string
+}
+
+// DeleteSectionCmd moves a section together with its notes to the trash
+type DeleteSectionCmd struct {
+	SectionID int
+}
+
+// SortByDueCmd reorders a section so the notes due first come first
+type SortByDueCmd struct {
+	SectionID int
+}
+
+type MoveSectionCmd struct {
+	SectionID int
+	ToOrder   int
+}
+
+// SaveViewCmd saves a named query, replacing the view with the same name
+type SaveViewCmd struct {
+	Name  string
+	Query string
+}
+
+type DeleteViewCmd struct {
+	Name string
+}
+
+// RestoreNoteCmd takes a note out of the trash and puts it at the bottom of
+// its section, or of the first section when its own is gone
+type RestoreNoteCmd struct {
+	ID int
+}
+
+// RestoreSectionCmd takes a section out of the trash and puts it on the right
+type RestoreSectionCmd struct {
+	ID int
+}
+
+// PurgeNoteCmd deletes a note in the trash for good
+type PurgeNoteCmd struct {
+	ID int
+}
+
+// PurgeSectionCmd deletes a section in the trash for good, with all its notes
+type PurgeSectionCmd struct {
+	ID int
+}
+
+// PurgeTrashCmd deletes everything that went into the trash before Before for
+// good. The zero time empties the whole trash.
+type PurgeTrashCmd struct {
+	Before time.Time
+}
+
+// ArchiveNoteCmd puts a note away in the archive
+type ArchiveNoteCmd struct {
+	SectionID int
+	Order     int
+}
+
+// ArchiveCheckedCmd archives every checked note of a section
+type ArchiveCheckedCmd struct {
+	SectionID int
+}
+
+// ArchiveOlderCmd archives every note on the board that was checked before
+// Before, see checkedAt
+type ArchiveOlderCmd struct {
+	Before time.Time
+}
+
+// UnarchiveNoteCmd brings a note back to the bottom of its section, or of the
+// first section when its own is gone
+type UnarchiveNoteCmd struct {
+	ID int
+}
+
+// ImportBoardCmd adds the sections and notes of an imported file to the board,
+// see import.go. Replace throws the board away first.
+type ImportBoardCmd struct {
+	Board   ImportedBoard
+	Replace bool
+}
+
+// ReplaceBoardCmd swaps the whole board for another one
+type ReplaceBoardCmd struct {
+	Board BoardState
+}
+
+func (AddNoteCmd) Kind() string        { return "AddNote" }
+func (EditNoteCmd) Kind() string       { return "EditNote" }
+func (DescribeNoteCmd) Kind() string   { return "DescribeNote" }
+func (TagNoteCmd) Kind() string        { return "TagNote" }
+func (DueNoteCmd) Kind() string        { return "DueNote" }
+func (ToggleNoteCmd) Kind() string     { return "ToggleNote" }
+func (DeleteNoteCmd) Kind() string     { return "DeleteNote" }
+func (MoveNoteCmd) Kind() string       { return "MoveNote" }
+func (AddSectionCmd) Kind() string     { return "AddSection" }
+func (RenameSectionCmd) Kind() string  { return "RenameSection" }
+func (DeleteSectionCmd) Kind() string  { return "DeleteSection" }
+func (SortByDueCmd) Kind() string      { return "SortByDue" }
+func (MoveSectionCmd) Kind() string    { return "MoveSection" }
+func (SaveViewCmd) Kind() string       { return "SaveView" }
+func (DeleteViewCmd) Kind() string     { return "DeleteView" }
+func (RestoreNoteCmd) Kind() string    { return "RestoreNote" }
+func (RestoreSectionCmd) Kind() string { return "RestoreSection" }
+func (PurgeNoteCmd) Kind() string      { return "PurgeNote" }
+func (PurgeSectionCmd) Kind() string   { return "PurgeSection" }
+func (PurgeTrashCmd) Kind() string     { return "PurgeTrash" }
+func (ArchiveNoteCmd) Kind() string    { return "ArchiveNote" }
+func (ArchiveCheckedCmd) Kind() string { return "ArchiveChecked" }
+func (ArchiveOlderCmd) Kind() string   { return "ArchiveOlder" }
+func (UnarchiveNoteCmd) Kind() string  { return "UnarchiveNote" }
+func (ImportBoardCmd) Kind() string    { return "ImportBoard" }
+func (ReplaceBoardCmd) Kind() string   { return "ReplaceBoard" }
+
+// Apply is the reducer, the only place where a board gets changed. at is the
+// time the change happened, passed in so replaying a command gives the same board.
+func (b *BoardState) Apply(cmd Command, at time.Time) error {
+	switch c := cmd.(type) {
+	case AddNoteCmd:
+		if _, ok := b.SectionByID(c.SectionID); !ok {
+			return ErrNoSuchSection
+		}
+		note := NewNote(c.Content, len(b.NotesIn(c.SectionID)), c.SectionID, b.NewID())
+		note.DateCreated = at
+		note.DateUpdated = at
+		b.Notes = append(b.Notes, note)
+
+	case EditNoteCmd:
+		note := b.NoteAt(c.SectionID, c.Order)
+		if note == nil {
+			return ErrNoSuchNote
+		}
+		note.Content = c.Content
+		note.DateUpdated = at
+
+	case DescribeNoteCmd:
+		note := b.NoteAt(c.SectionID, c.Order)
+		if note == nil {
+			return ErrNoSuchNote
+		}
+		note.Description = c.Description
+		note.DateUpdated = at
+
+	case TagNoteCmd:
+		note := b.NoteAt(c.SectionID, c.Order)
+		if note == nil {
+			return ErrNoSuchNote
+		}
+		note.Tags = slices.Clone(c.Tags)
+		note.DateUpdated = at
+
+	case DueNoteCmd:
+		note := b.NoteAt(c.SectionID, c.Order)
+		if note == nil {
+			return ErrNoSuchNote
+		}
+		note.DueDate = c.DueDate
+		note.DateUpdated = at
+
+	case ToggleNoteCmd:
+		note := b.NoteAt(c.SectionID, c.Order)
+		if note == nil {
+			return ErrNoSuchNote
+		}
+		note.IsChecked = !note.IsChecked
+		note.DateChecked = time.Time{}
+		if note.IsChecked {
+			note.DateChecked = at
+		}
+		note.DateUpdated = at
+
+	case DeleteNoteCmd:
+		note := b.NoteAt(c.SectionID, c.Order)
+		if note == nil {
+			return ErrNoSuchNote
+		}
+		note.IsDeleted = true
+		note.DateDeleted = at
+		RecalulateNoteOrder(b.NotesIn(c.SectionID))
+
+	case MoveNoteCmd:
+		note := b.NoteAt(c.SectionID, c.Order)
+		if note == nil {
+			return ErrNoSuchNote
+		}
+		if _, ok := b.SectionByID(c.ToSectionID); !ok {
+			return ErrNoSuchSection
+		}
+
+		source := slices.DeleteFunc(b.NotesIn(c.SectionID), func(n *Note) bool { return n == note })
+		RecalulateNoteOrder(source)
+
+		target := b.NotesIn(c.ToSectionID)
+		if c.ToSectionID == c.SectionID {
+			target = source
+		}
+		to := c.ToOrder
+		if to < 0 || to > len(target) {
+			to = len(target)
+		}
+		target = slices.Insert(target, to, note)
+		for i, n := range target {
+			n.Order = i
+		}
+
+		note.SectionID = c.ToSectionID
+		note.DateUpdated = at
+
+	case AddSectionCmd:
+		if _, ok := b.SectionByID(c.ID); ok {
+			return fmt.Errorf("section id %d is already taken", c.ID)
+		}
+		b.useID(c.ID)
+		b.SectionData = append(b.SectionData, NewSection(c.Name, len(b.SectionData), c.ID))
+
+	case RenameSectionCmd:
+		section, ok := b.SectionByID(c.SectionID)
+		if !ok {
+			return ErrNoSuchSection
+		}
+		section.Name = c.Name
+
+	case DeleteSectionCmd:
+		section, ok := b.SectionByID(c.SectionID)
+		if !ok {
+			return ErrNoSuchSection
+		}
+		if len(b.SectionData) == 1 {
+			return ErrLastSection
+		}
+		// The notes stay as they are, they come back with the section
+		deleted := *section
+		deleted.DateDeleted = at
+		b.DeletedSections = append(b.DeletedSections, deleted)
+		b.SectionData = slices.DeleteFunc(b.SectionData, func(s Section) bool { return s.ID == c.SectionID })
+		RecalulateSectionOrder(b.SectionData)
+
+	case SortByDueCmd:
+		if _, ok := b.SectionByID(c.SectionID); !ok {
+			return ErrNoSuchSection
+		}
+		notes := b.NotesIn(c.SectionID)
+		sortByDue(notes)
+		for i, n := range notes {
+			n.Order = i
+		}
+
+	case MoveSectionCmd:
+		section, ok := b.SectionByID(c.SectionID)
+		if !ok {
+			return ErrNoSuchSection
+		}
+		to := clamp(0, c.ToOrder, len(b.SectionData)-1)
+		from := section.Order
+		// Shift everything between the old and the new place by one
+		for i := range b.SectionData {
+			s := &b.SectionData[i]
+			switch {
+			case s.ID == c.SectionID:
+				s.Order = to
+			case from < to && s.Order > from && s.Order <= to:
+				s.Order--
+			case to < from && s.Order >= to && s.Order < from:
+				s.Order++
+			}
+		}
+		RecalulateSectionOrder(b.SectionData)
+
+	case RestoreNoteCmd:
+		note := b.NoteByID(c.ID)
+		if note == nil || !note.IsDeleted {
+			return ErrNoSuchNote
+		}
+		b.putBack(note)
+		note.IsDeleted = false
+		note.DateDeleted = time.Time{}
+		note.DateUpdated = at
+
+	case RestoreSectionCmd:
+		idx := slices.IndexFunc(b.DeletedSections, func(s Section) bool { return s.ID == c.ID })
+		if idx == -1 {
+			return ErrNoSuchSection
+		}
+		section := b.DeletedSections[idx]
+		section.Order = len(b.SectionData)
+		section.DateDeleted = time.Time{}
+		b.SectionData = append(b.SectionData, section)
+		b.DeletedSections = slices.Delete(b.DeletedSections, idx, idx+1)
+
+	case PurgeNoteCmd:
+		note := b.NoteByID(c.ID)
+		if note == nil || !note.IsDeleted {
+			return ErrNoSuchNote
 		}
+		b.Notes = slices.DeleteFunc(b.Notes, func(n *Note) bool { return n == note })
 
-		sectionIdx := slices.IndexFunc(m.SectionData, func(sec Section) bool {
-			return sec.Order == m.UIControl.SectionCursor
+	case PurgeSectionCmd:
+		idx := slices.IndexFunc(b.DeletedSections, func(s Section) bool { return s.ID == c.ID })
+		if idx == -1 {
+			return ErrNoSuchSection
+		}
+		b.Notes = slices.DeleteFunc(b.Notes, func(n *Note) bool { return n.SectionID == c.ID })
+		b.DeletedSections = slices.Delete(b.DeletedSections, idx, idx+1)
+
+	case PurgeTrashCmd:
+		expired := func(t time.Time) bool { return c.Before.IsZero() || t.Before(c.Before) }
+		gone := []int{}
+		for _, s := range b.DeletedSections {
+			if expired(s.DateDeleted) {
+				gone = append(gone, s.ID)
+			}
+		}
+		b.DeletedSections = slices.DeleteFunc(b.DeletedSections, func(s Section) bool { return slices.Contains(gone, s.ID) })
+		b.Notes = slices.DeleteFunc(b.Notes, func(n *Note) bool {
+			return slices.Contains(gone, n.SectionID) || (n.IsDeleted && expired(n.DateDeleted))
 		})
-		/*TODO There must be better way to link the data. This manually delete is not bad but
-		I have a feeling that it can be better
-		*/
-		/*
-			Maybe I should just manipulate the original array and repopulate displayOrder every Update
-		*/
-		if sectionIdx != -1 {
-			sec := m.SectionData[sectionIdx]
-			buffer := NewNote(content, maxOrder+1, sec.ID)
-			tmp := append(sectionNotePtrs, buffer)
-			m.UIControl.DisplayOrder[section.ID] = tmp
-
-			m.Notes = slices.DeleteFunc(m.Notes, func(n *Note) bool {
-				return n.SectionID == sec.ID
-			})
-
-			m.Notes = append(m.Notes, tmp...)
+
+	case ArchiveNoteCmd:
+		note := b.NoteAt(c.SectionID, c.Order)
+		if note == nil {
+			return ErrNoSuchNote
+		}
+		b.archive(note, at)
+		RecalulateNoteOrder(b.NotesIn(c.SectionID))
+
+	case ArchiveCheckedCmd:
+		if _, ok := b.SectionByID(c.SectionID); !ok {
+			return ErrNoSuchSection
+		}
+		for _, n := range b.NotesIn(c.SectionID) {
+			if n.IsChecked {
+				b.archive(n, at)
+			}
+		}
+		RecalulateNoteOrder(b.NotesIn(c.SectionID))
+
+	case ArchiveOlderCmd:
+		for _, section := range b.SectionData {
+			for _, n := range b.NotesIn(section.ID) {
+				if n.IsChecked && checkedAt(n).Before(c.Before) {
+					b.archive(n, at)
+				}
+			}
+			RecalulateNoteOrder(b.NotesIn(section.ID))
 		}
+
+	case UnarchiveNoteCmd:
+		note := b.NoteByID(c.ID)
+		if note == nil || !note.IsArchived {
+			return ErrNoSuchNote
+		}
+		b.putBack(note)
+		note.IsArchived = false
+		note.DateArchived = time.Time{}
+		note.DateUpdated = at
+
+	case SaveViewCmd:
+		view := SavedView{Name: c.Name, Query: c.Query}
+		if idx := slices.IndexFunc(b.Views, func(v SavedView) bool { return v.Name == c.Name }); idx != -1 {
+			b.Views[idx] = view
+		} else {
+			b.Views = append(b.Views, view)
+		}
+
+	case DeleteViewCmd:
+		idx := slices.IndexFunc(b.Views, func(v SavedView) bool { return v.Name == c.Name })
+		if idx == -1 {
+			return ErrNoSuchView
+		}
+		b.Views = slices.Delete(b.Views, idx, idx+1)
+
+	case ImportBoardCmd:
+		return b.importBoard(c, at)
+
+	case ReplaceBoardCmd:
+		*b = CloneBoardState(c.Board)
+
+	default:
+		return fmt.Errorf("unknown command %T", cmd)
+	}
+	return nil
+}
+
+// Apply runs cmd against the board on screen and records it for undo and saving
+func (m *ProgramModel) Apply(cmd Command) error {
+	before := m.Snapshot()
+	at := time.Now()
+	if err := m.BoardState.Apply(cmd, at); err != nil {
+		return err
+	}
+	m.journal(cmd, at)
+	m.RecordChange(before)
+	m.RepopulateDisplayOrder()
+	return nil
+}
+
+func (b *BoardState) archive(n *Note, at time.Time) {
+	n.IsArchived = true
+	n.DateArchived = at
+}
+
+// putBack moves a note coming back from the trash or the archive to the bottom
+// of its section, or of the first section when its own is gone
+func (b *BoardState) putBack(n *Note) {
+	if _, ok := b.SectionByID(n.SectionID); !ok {
+		first, _ := FindSectionDataByOrder(b.SectionData, 0)
+		n.SectionID = first.ID
 	}
-	return true
+	n.Order = len(b.NotesIn(n.SectionID))
 }
 
-func EditNote(note *Note, content string) bool {
-	if note != nil {
-		note.Content = content
-		return true
+func (b *BoardState) SectionByID(id int) (*Section, bool) {
+	idx := slices.IndexFunc(b.SectionData, func(s Section) bool { return s.ID == id })
+	if idx == -1 {
+		return &Section{}, false
 	}
-	return false
+	return &b.SectionData[idx], true
+}
+
+// IsActive tells whether n is on the board. Notes of a section in the trash
+// went there with it, even though they aren't deleted themselves.
+func (b *BoardState) IsActive(n *Note) bool {
+	_, ok := b.SectionByID(n.SectionID)
+	return ok && n.IsActive()
 }
 
-func EditSection(section *Section, content string) bool {
-	if section != nil {
-		section.Name = content
-		return true
+// NotesIn returns the notes of a section sorted by Order, leaving out the
+// ones in the trash or the archive. A section in the trash has none.
+func (b *BoardState) NotesIn(sectionID int) []*Note {
+	notes := []*Note{}
+	if _, ok := b.SectionByID(sectionID); !ok {
+		return notes
 	}
-	return false
+	for _, n := range b.Notes {
+		if n.SectionID == sectionID && n.IsActive() {
+			notes = append(notes, n)
+		}
+	}
+	slices.SortFunc(notes, func(a, b *Note) int { return a.Order - b.Order })
+	return notes
+}
+
+func (b *BoardState) NoteAt(sectionID int, order int) *Note {
+	return FindNoteByItsOrder(b.NotesIn(sectionID), order)
 }
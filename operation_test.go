@@ -0,0 +1,281 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	inbox = 0
+	doing = 1
+)
+
+var testNow = time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+
+// testBoard has the sections Inbox and Doing, the notes added to it get IDs from 2 on
+func testBoard() BoardState {
+	return BoardState{
+		Notes:       []*Note{},
+		SectionData: []Section{{ID: inbox, Order: 0, Name: "Inbox"}, {ID: doing, Order: 1, Name: "Doing"}},
+		NextID:      2,
+	}
+}
+
+// layout writes the board down as "Inbox: a [x]b; Doing: c", followed by what
+// is in the trash, the archive and the views. Notes whose section is gone
+// for good show up as lost.
+func layout(b *BoardState) string {
+	title := func(n *Note) string {
+		if n.IsChecked {
+			return "[x]" + n.Content
+		}
+		return n.Content
+	}
+	titlesIn := func(sectionID int) string {
+		words := []string{}
+		for _, n := range b.NotesIn(sectionID) {
+			words = append(words, title(n))
+		}
+		return strings.Join(words, " ")
+	}
+
+	parts := []string{}
+	known := map[int]bool{}
+	for i := range b.SectionData {
+		section, _ := FindSectionDataByOrder(b.SectionData, i)
+		known[section.ID] = true
+		if titles := titlesIn(section.ID); titles != "" {
+			parts = append(parts, section.Name+": "+titles)
+		} else {
+			parts = append(parts, section.Name)
+		}
+	}
+
+	trash, archive, lost := []string{}, []string{}, []string{}
+	for _, s := range b.DeletedSections {
+		known[s.ID] = true
+		// NotesIn has nothing for a section in the trash, its notes went with it
+		words := []string{}
+		for _, n := range b.Notes {
+			if n.SectionID == s.ID && n.IsActive() {
+				words = append(words, title(n))
+			}
+		}
+		trash = append(trash, fmt.Sprintf("%s(%s)", s.Name, strings.Join(words, " ")))
+	}
+	for _, n := range b.Notes {
+		switch {
+		case !known[n.SectionID]:
+			lost = append(lost, title(n))
+		case n.IsDeleted:
+			trash = append(trash, title(n))
+		case n.IsArchived:
+			archive = append(archive, title(n))
+		}
+	}
+	for i, list := range [][]string{trash, archive, lost} {
+		if len(list) > 0 {
+			parts = append(parts, []string{"trash", "archive", "lost"}[i]+": "+strings.Join(list, " "))
+		}
+	}
+	for _, v := range b.Views {
+		parts = append(parts, "view "+v.Name+": "+v.Query)
+	}
+	return strings.Join(parts, "; ")
+}
+
+func TestApply(t *testing.T) {
+	// Every command runs an hour after the one before it
+	hour := func(i int) time.Time { return testNow.Add(time.Duration(i) * time.Hour) }
+	add := func(section int, titles ...string) []Command {
+		cmds := []Command{}
+		for _, title := range titles {
+			cmds = append(cmds, AddNoteCmd{SectionID: section, Content: title})
+		}
+		return cmds
+	}
+	then := func(groups ...[]Command) []Command {
+		cmds := []Command{}
+		for _, g := range groups {
+			cmds = append(cmds, g...)
+		}
+		return cmds
+	}
+	one := func(cmd Command) []Command { return []Command{cmd} }
+
+	tests := []struct {
+		name    string
+		cmds    []Command
+		want    string
+		wantErr error // Returned by the last command, which leaves the board as it was
+	}{
+		{"add", add(inbox, "a", "b"), "Inbox: a b; Doing", nil},
+		{"add to a missing section", add(7, "a"), "Inbox; Doing", ErrNoSuchSection},
+		{"edit", then(add(inbox, "a"), one(EditNoteCmd{SectionID: inbox, Order: 0, Content: "b"})), "Inbox: b; Doing", nil},
+		{"edit a missing note", then(add(inbox, "a"), one(EditNoteCmd{SectionID: inbox, Order: 1, Content: "b"})), "Inbox: a; Doing", ErrNoSuchNote},
+		{
+			"toggle twice",
+			then(add(inbox, "a", "b"), one(ToggleNoteCmd{SectionID: inbox, Order: 0}), one(ToggleNoteCmd{SectionID: inbox, Order: 1}), one(ToggleNoteCmd{SectionID: inbox, Order: 1})),
+			"Inbox: [x]a b; Doing", nil,
+		},
+		{"delete", then(add(inbox, "a", "b", "c"), one(DeleteNoteCmd{SectionID: inbox, Order: 1})), "Inbox: a c; Doing; trash: b", nil},
+		{"delete a missing note", then(add(inbox, "a"), one(DeleteNoteCmd{SectionID: doing, Order: 0})), "Inbox: a; Doing", ErrNoSuchNote},
+		{"move down", then(add(inbox, "a", "b", "c"), one(MoveNoteCmd{SectionID: inbox, Order: 0, ToSectionID: inbox, ToOrder: 2})), "Inbox: b c a; Doing", nil},
+		{"move up", then(add(inbox, "a", "b", "c"), one(MoveNoteCmd{SectionID: inbox, Order: 2, ToSectionID: inbox, ToOrder: 0})), "Inbox: c a b; Doing", nil},
+		{
+			"move to the bottom of another section",
+			then(add(inbox, "a", "b"), add(doing, "c"), one(MoveNoteCmd{SectionID: inbox, Order: 0, ToSectionID: doing, ToOrder: -1})),
+			"Inbox: b; Doing: c a", nil,
+		},
+		{
+			"move into the middle of another section",
+			then(add(inbox, "a"), add(doing, "b", "c"), one(MoveNoteCmd{SectionID: inbox, Order: 0, ToSectionID: doing, ToOrder: 1})),
+			"Inbox; Doing: b a c", nil,
+		},
+		{"move to a missing section", then(add(inbox, "a"), one(MoveNoteCmd{SectionID: inbox, Order: 0, ToSectionID: 7})), "Inbox: a; Doing", ErrNoSuchSection},
+		{
+			// b and d go to the trash, moving a by one lands it after c
+			"move past hidden notes",
+			then(
+				add(inbox, "a", "b", "c", "d", "e"),
+				one(DeleteNoteCmd{SectionID: inbox, Order: 1}),
+				one(DeleteNoteCmd{SectionID: inbox, Order: 2}),
+				one(MoveNoteCmd{SectionID: inbox, Order: 0, ToSectionID: inbox, ToOrder: 1}),
+			),
+			"Inbox: c a e; Doing; trash: b d", nil,
+		},
+		{
+			"hidden notes come back at the bottom",
+			then(
+				add(inbox, "a", "b", "c", "d", "e"),
+				one(DeleteNoteCmd{SectionID: inbox, Order: 1}),
+				one(DeleteNoteCmd{SectionID: inbox, Order: 2}),
+				one(MoveNoteCmd{SectionID: inbox, Order: 0, ToSectionID: inbox, ToOrder: 1}),
+				one(RestoreNoteCmd{ID: 3}),
+				one(RestoreNoteCmd{ID: 5}),
+			),
+			"Inbox: c a e b d; Doing", nil,
+		},
+		{
+			"move into a section with hidden notes",
+			then(add(inbox, "a"), add(doing, "b", "c"), one(DeleteNoteCmd{SectionID: doing, Order: 0}), one(MoveNoteCmd{SectionID: inbox, Order: 0, ToSectionID: doing, ToOrder: 1})),
+			"Inbox; Doing: c a; trash: b", nil,
+		},
+		{"add section", one(AddSectionCmd{ID: 5, Name: "Done"}), "Inbox; Doing; Done", nil},
+		{"rename section", one(RenameSectionCmd{SectionID: doing, Name: "Now"}), "Inbox; Now", nil},
+		{"move section", one(MoveSectionCmd{SectionID: doing, ToOrder: 0}), "Doing; Inbox", nil},
+		{"move section past the end", one(MoveSectionCmd{SectionID: inbox, ToOrder: 9}), "Doing; Inbox", nil},
+		{"delete section", then(add(doing, "a"), one(DeleteSectionCmd{SectionID: doing})), "Inbox; trash: Doing(a)", nil},
+		{"delete the last section", then(one(DeleteSectionCmd{SectionID: doing}), one(DeleteSectionCmd{SectionID: inbox})), "Inbox; trash: Doing()", ErrLastSection},
+		{
+			"restore section",
+			then(add(doing, "a"), one(DeleteSectionCmd{SectionID: doing}), one(AddSectionCmd{ID: 5, Name: "Done"}), one(RestoreSectionCmd{ID: doing})),
+			"Inbox; Done; Doing: a", nil,
+		},
+		{
+			"restore a note of a deleted section",
+			then(add(doing, "a", "b"), one(DeleteNoteCmd{SectionID: doing, Order: 0}), one(DeleteSectionCmd{SectionID: doing}), one(RestoreNoteCmd{ID: 2})),
+			"Inbox: a; trash: Doing(b)", nil,
+		},
+		{
+			"notes of a deleted section can't be changed",
+			then(add(doing, "a"), one(DeleteSectionCmd{SectionID: doing}), one(ToggleNoteCmd{SectionID: doing, Order: 0})),
+			"Inbox; trash: Doing(a)", ErrNoSuchNote,
+		},
+		{"restore a note that isn't in the trash", then(add(inbox, "a"), one(RestoreNoteCmd{ID: 2})), "Inbox: a; Doing", ErrNoSuchNote},
+		{"purge note", then(add(inbox, "a", "b"), one(DeleteNoteCmd{SectionID: inbox, Order: 0}), one(PurgeNoteCmd{ID: 2})), "Inbox: b; Doing", nil},
+		{"purge a note that isn't in the trash", then(add(inbox, "a"), one(PurgeNoteCmd{ID: 2})), "Inbox: a; Doing", ErrNoSuchNote},
+		{
+			"purge deleted section",
+			then(add(inbox, "a"), add(doing, "b", "c"), one(DeleteNoteCmd{SectionID: doing, Order: 0}), one(DeleteSectionCmd{SectionID: doing}), one(PurgeSectionCmd{ID: doing})),
+			"Inbox: a", nil,
+		},
+		{"purge a section that isn't in the trash", one(PurgeSectionCmd{ID: doing}), "Inbox; Doing", ErrNoSuchSection},
+		{
+			"purge trash",
+			then(add(inbox, "a", "b"), add(doing, "c"), one(DeleteNoteCmd{SectionID: inbox, Order: 0}), one(DeleteSectionCmd{SectionID: doing}), one(DeleteNoteCmd{SectionID: inbox, Order: 0}), one(PurgeTrashCmd{})),
+			"Inbox", nil,
+		},
+		{
+			// a went into the trash at hour 3, Doing at hour 4 and b at hour 5
+			"purge trash before a time",
+			then(add(inbox, "a", "b"), add(doing, "c"), one(DeleteNoteCmd{SectionID: inbox, Order: 0}), one(DeleteSectionCmd{SectionID: doing}), one(DeleteNoteCmd{SectionID: inbox, Order: 0}), one(PurgeTrashCmd{Before: hour(5)})),
+			"Inbox; trash: b", nil,
+		},
+		{
+			"sort by due",
+			then(
+				add(inbox, "a", "b", "c"),
+				one(DueNoteCmd{SectionID: inbox, Order: 0, DueDate: testNow.AddDate(0, 0, 3)}),
+				one(DueNoteCmd{SectionID: inbox, Order: 2, DueDate: testNow.AddDate(0, 0, 1)}),
+				one(SortByDueCmd{SectionID: inbox}),
+			),
+			"Inbox: c a b; Doing", nil,
+		},
+		{
+			"archive checked",
+			then(add(inbox, "a", "b", "c"), add(doing, "d"), one(ToggleNoteCmd{SectionID: inbox, Order: 1}), one(ToggleNoteCmd{SectionID: doing, Order: 0}), one(ArchiveCheckedCmd{SectionID: inbox})),
+			"Inbox: a c; Doing: [x]d; archive: [x]b", nil,
+		},
+		{
+			// a is checked at hour 3 and c at hour 4
+			"archive older",
+			then(add(inbox, "a", "b"), add(doing, "c"), one(ToggleNoteCmd{SectionID: inbox, Order: 0}), one(ToggleNoteCmd{SectionID: doing, Order: 0}), one(ArchiveOlderCmd{Before: hour(4)})),
+			"Inbox: b; Doing: [x]c; archive: [x]a", nil,
+		},
+		{"unarchive a note that isn't archived", then(add(inbox, "a"), one(UnarchiveNoteCmd{ID: 2})), "Inbox: a; Doing", ErrNoSuchNote},
+		{"replace", then(add(inbox, "a"), one(ReplaceBoardCmd{Board: testBoard()})), "Inbox; Doing", nil},
+		{
+			"save views",
+			then(one(SaveViewCmd{Name: "mine", Query: "tag:me"}), one(SaveViewCmd{Name: "late", Query: "is:overdue"}), one(SaveViewCmd{Name: "mine", Query: "tag:me is:open"})),
+			"Inbox; Doing; view mine: tag:me is:open; view late: is:overdue", nil,
+		},
+		{"delete view", then(one(SaveViewCmd{Name: "mine", Query: "tag:me"}), one(DeleteViewCmd{Name: "mine"})), "Inbox; Doing", nil},
+		{"delete a missing view", one(DeleteViewCmd{Name: "mine"}), "Inbox; Doing", ErrNoSuchView},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := testBoard()
+			for i, cmd := range tt.cmds {
+				err := b.Apply(cmd, hour(i))
+				if i < len(tt.cmds)-1 || tt.wantErr == nil {
+					if err != nil {
+						t.Fatalf("%s: %v", cmd.Kind(), err)
+					}
+				} else if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("%s returned %v, want %v", cmd.Kind(), err, tt.wantErr)
+				}
+			}
+			if got := layout(&b); got != tt.want {
+				t.Errorf("got  %q\nwant %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplyKeepsTimes(t *testing.T) {
+	b := testBoard()
+	b.Apply(AddNoteCmd{SectionID: inbox, Content: "a"}, testNow)
+	b.Apply(EditNoteCmd{SectionID: inbox, Order: 0, Content: "b"}, testNow.Add(time.Hour))
+	note := b.NoteAt(inbox, 0)
+	if !note.DateCreated.Equal(testNow) || !note.DateUpdated.Equal(testNow.Add(time.Hour)) {
+		t.Errorf("created %s and updated %s", note.DateCreated, note.DateUpdated)
+	}
+}
+
+// The replaced board shares no notes with the command, so replaying it twice gives the same board
+func TestReplaceBoardCopies(t *testing.T) {
+	board := testBoard()
+	board.Apply(AddNoteCmd{SectionID: inbox, Content: "a"}, testNow)
+	cmd := ReplaceBoardCmd{Board: board}
+
+	b := testBoard()
+	b.Apply(cmd, testNow)
+	b.Apply(EditNoteCmd{SectionID: inbox, Order: 0, Content: "b"}, testNow)
+	if cmd.Board.Notes[0].Content != "a" {
+		t.Error("editing the board changed the command")
+	}
+}
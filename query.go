@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/*
+A query picks notes by their fields. It is a list of terms separated by
+spaces and a note has to match every one of them:
+
+	milk                 title or description contains milk
+	"buy milk"           the same with spaces
+	tag:backend #backend has the tag
+	section:doing        is in a section whose name contains doing
+	is:checked is:open   checked or not
+	is:overdue           past its due date and not checked
+	is:tagged            has any tag
+	due:today due:week   due today, or this week (Monday to Sunday)
+	due:none due:any     has no due date, or has one
+	due:soon due:overdue due today or tomorrow, or past due
+	due:<fri             due before a date, any date parseDue reads
+	due:>2026-11-01      due after a date
+	created:>7d          created in the last 7 days
+	updated:<2026-10-01  last changed before a date
+
+A term starting with - matches the notes the term doesn't match, e.g. -is:checked.
+*/
+
+// Query is a parsed query, see above
+type Query struct {
+	Text  string
+	terms []queryTerm
+}
+
+type queryTerm func(b *BoardState, n *Note) bool
+
+// SavedView is a query the user gave a name, saved with the board
+type SavedView struct {
+	Name  string
+	Query string
+}
+
+// ParseQuery reads s, relative dates in it are taken from now
+func ParseQuery(s string, now time.Time) (Query, error) {
+	q := Query{Text: s}
+	for _, word := range splitQuery(s) {
+		negate := false
+		if len(word) > 1 && word[0] == '-' {
+			negate = true
+			word = word[1:]
+		}
+
+		term, err := parseTerm(word, now)
+		if err != nil {
+			return Query{}, err
+		}
+		if negate {
+			positive := term
+			term = func(b *BoardState, n *Note) bool { return !positive(b, n) }
+		}
+		q.terms = append(q.terms, term)
+	}
+	return q, nil
+}
+
+// Matches tells whether n, a note of b, matches every term
+func (q Query) Matches(b *BoardState, n *Note) bool {
+	for _, term := range q.terms {
+		if !term(b, n) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitQuery splits at spaces, except the ones inside double quotes
+func splitQuery(s string) []string {
+	words := []string{}
+	word := strings.Builder{}
+	quoted := false
+	for _, r := range s {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case unicode.IsSpace(r) && !quoted:
+			if word.Len() > 0 {
+				words = append(words, word.String())
+				word.Reset()
+			}
+		default:
+			word.WriteRune(r)
+		}
+	}
+	if word.Len() > 0 {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+func parseTerm(word string, now time.Time) (queryTerm, error) {
+	if tag, ok := strings.CutPrefix(word, "#"); ok && tag != "" {
+		return tagTerm(tag), nil
+	}
+
+	key, value, ok := strings.Cut(word, ":")
+	if !ok {
+		return textTerm(word), nil
+	}
+	value = strings.ToLower(value)
+
+	switch strings.ToLower(key) {
+	case "tag":
+		return tagTerm(value), nil
+
+	case "section":
+		return func(b *BoardState, n *Note) bool {
+			section, _ := b.SectionByID(n.SectionID)
+			return strings.Contains(strings.ToLower(section.Name), value)
+		}, nil
+
+	case "is":
+		switch value {
+		case "checked", "done":
+			return func(b *BoardState, n *Note) bool { return n.IsChecked }, nil
+		case "open", "unchecked":
+			return func(b *BoardState, n *Note) bool { return !n.IsChecked }, nil
+		case "overdue":
+			return func(b *BoardState, n *Note) bool { return n.DueStatus(now) == Overdue }, nil
+		case "tagged":
+			return func(b *BoardState, n *Note) bool { return len(n.Tags) > 0 }, nil
+		}
+		return nil, fmt.Errorf("unknown is:%s, try is:checked, is:open, is:overdue or is:tagged", value)
+
+	case "due":
+		return dueTerm(value, now)
+
+	case "created":
+		return dateTerm(value, now, true, func(n *Note) time.Time { return n.DateCreated })
+
+	case "updated":
+		return dateTerm(value, now, true, func(n *Note) time.Time { return n.DateUpdated })
+	}
+
+	// Not a known key, "note:1" is just text
+	return textTerm(word), nil
+}
+
+func textTerm(text string) queryTerm {
+	text = strings.ToLower(text)
+	return func(b *BoardState, n *Note) bool {
+		return strings.Contains(strings.ToLower(n.Content), text) ||
+			strings.Contains(strings.ToLower(n.Description), text)
+	}
+}
+
+func tagTerm(tag string) queryTerm {
+	tag = strings.ToLower(tag)
+	return func(b *BoardState, n *Note) bool { return slices.Contains(n.Tags, tag) }
+}
+
+func dueTerm(value string, now time.Time) (queryTerm, error) {
+	switch value {
+	case "none":
+		return func(b *BoardState, n *Note) bool { return n.DueDate.IsZero() }, nil
+	case "any":
+		return func(b *BoardState, n *Note) bool { return !n.DueDate.IsZero() }, nil
+	case "overdue":
+		return func(b *BoardState, n *Note) bool { return n.DueStatus(now) == Overdue }, nil
+	case "soon":
+		return func(b *BoardState, n *Note) bool { return n.DueStatus(now) == DueSoon }, nil
+	case "week":
+		// Monday to Sunday of the current week
+		monday := startOfDay(now).AddDate(0, 0, -(int(now.Weekday())+6)%7)
+		nextMonday := monday.AddDate(0, 0, 7)
+		return func(b *BoardState, n *Note) bool {
+			return !n.DueDate.IsZero() && !n.DueDate.Before(monday) && n.DueDate.Before(nextMonday)
+		}, nil
+	}
+
+	return dateTerm(value, now, false, func(n *Note) time.Time { return n.DueDate })
+}
+
+// dateTerm compares a date of the note with "<date", ">date" or "date" (the
+// same day). Dates in the past (created, updated) count "7d" backwards, seven
+// days ago instead of seven days from now.
+func dateTerm(value string, now time.Time, past bool, field func(n *Note) time.Time) (queryTerm, error) {
+	op := ""
+	if value != "" && (value[0] == '<' || value[0] == '>') {
+		op, value = value[:1], value[1:]
+	}
+
+	day, err := parseDue(value, now)
+	if n, unit, ok := parseOffset(value); ok && past {
+		if ago, ok := addOffset(startOfDay(now), -n, unit); ok {
+			day, err = ago, nil
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	if day.IsZero() {
+		return nil, fmt.Errorf("a date is missing after %q", op)
+	}
+	next := day.AddDate(0, 0, 1)
+
+	return func(b *BoardState, n *Note) bool {
+		t := field(n)
+		if t.IsZero() {
+			return false
+		}
+		switch op {
+		case "<":
+			return t.Before(day)
+		case ">":
+			return !t.Before(next)
+		}
+		return !t.Before(day) && t.Before(next)
+	}, nil
+}
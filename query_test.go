@@ -0,0 +1,110 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseQuery(t *testing.T) {
+	// testNow is a Sunday, the week runs from Monday the 12th to Sunday the 18th
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.Local) }
+	b := testBoard()
+	for _, step := range []struct {
+		cmd Command
+		at  time.Time
+	}{
+		{AddNoteCmd{SectionID: inbox, Content: "Buy milk"}, testNow},
+		{DescribeNoteCmd{SectionID: inbox, Order: 0, Description: "Semi-skimmed"}, testNow},
+		{TagNoteCmd{SectionID: inbox, Order: 0, Tags: []string{"home"}}, testNow},
+		{DueNoteCmd{SectionID: inbox, Order: 0, DueDate: day(18)}, testNow},
+		{AddNoteCmd{SectionID: doing, Content: "Write the docs"}, day(15)},
+		{TagNoteCmd{SectionID: doing, Order: 0, Tags: []string{"docs", "work"}}, day(15)},
+		{DueNoteCmd{SectionID: doing, Order: 0, DueDate: day(20)}, day(15)},
+		{ToggleNoteCmd{SectionID: doing, Order: 0}, day(16)},
+		{AddNoteCmd{SectionID: doing, Content: "Fix the bug"}, day(1)},
+		{TagNoteCmd{SectionID: doing, Order: 1, Tags: []string{"work"}}, day(1)},
+		{DueNoteCmd{SectionID: doing, Order: 1, DueDate: day(10)}, day(1)},
+		{AddNoteCmd{SectionID: inbox, Content: "Plan the trip"}, day(5)},
+	} {
+		if err := b.Apply(step.cmd, step.at); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		query   string
+		want    string // Titles of the matching notes, separated by commas
+		wantErr bool
+	}{
+		{"", "Buy milk, Write the docs, Fix the bug, Plan the trip", false},
+		{"MILK", "Buy milk", false},
+		{"skimmed", "Buy milk", false},
+		{`"the docs"`, "Write the docs", false},
+		{"the docs", "Write the docs", false},
+		{"tag:work", "Write the docs, Fix the bug", false},
+		{"#Work", "Write the docs, Fix the bug", false},
+		{"section:do", "Write the docs, Fix the bug", false},
+		{"is:checked", "Write the docs", false},
+		{"is:open", "Buy milk, Fix the bug, Plan the trip", false},
+		{"is:overdue", "Fix the bug", false},
+		{"is:tagged", "Buy milk, Write the docs, Fix the bug", false},
+		{"due:today", "Buy milk", false},
+		{"due:week", "Buy milk", false},
+		{"due:none", "Plan the trip", false},
+		{"due:any", "Buy milk, Write the docs, Fix the bug", false},
+		{"due:soon", "Buy milk", false},
+		{"due:overdue", "Fix the bug", false},
+		{"due:<tue", "Buy milk, Fix the bug", false},
+		{"due:>2026-10-18", "Write the docs", false},
+		{"due:2026-10-20", "Write the docs", false},
+		{"created:>7d", "Buy milk, Write the docs", false},
+		{"created:<2026-10-05", "Fix the bug", false},
+		{"updated:2026-10-16", "Write the docs", false},
+		{"-is:checked tag:work", "Fix the bug", false},
+		{"-#work -due:none", "Buy milk", false},
+		{"note:1", "", false},
+		{"is:late", "", true},
+		{"due:<", "", true},
+		{"due:someday", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := ParseQuery(tt.query, testNow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			matches := []string{}
+			for _, n := range b.Notes {
+				if q.Matches(&b, n) {
+					matches = append(matches, n.Content)
+				}
+			}
+			if got := strings.Join(matches, ", "); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitQuery(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{}},
+		{"  a   b ", []string{"a", "b"}},
+		{`"buy milk" tag:home`, []string{"buy milk", "tag:home"}},
+		{`-"buy milk"`, []string{"-buy milk"}},
+		{`"open`, []string{"open"}},
+	}
+	for _, tt := range tests {
+		if got := splitQuery(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("splitQuery(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
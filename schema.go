@@ -0,0 +1,107 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// currentSchemaVersion is written to every board file as schemaVersion.
+// Bump it together with a new entry in migrations whenever the saved
+// shape of BoardState changes in a way older files need help with.
+const currentSchemaVersion = 8
+
+// migration upgrades a decoded board document by exactly one version, in place
+type migration func(doc map[string]any) error
+
+// migrations[v] takes a document from version v to v+1
+var migrations = map[int]migration{
+	// Version 0 is every file saved before schemaVersion existed. The fields are the same.
+	0: func(doc map[string]any) error { return nil },
+	// Version 2 added NextID. RepairIDs fills it in and hands out the missing note IDs.
+	1: func(doc map[string]any) error { return nil },
+	// Version 3 added note descriptions. Older notes have none.
+	2: func(doc map[string]any) error { return nil },
+	// Version 4 added note tags. Older notes have none.
+	3: func(doc map[string]any) error { return nil },
+	// Version 5 added due dates. Older notes have none.
+	4: func(doc map[string]any) error { return nil },
+	// Version 6 added saved views. Older boards have none.
+	5: func(doc map[string]any) error { return nil },
+	// Version 7 added the trash: DeletedSections, IsDeleted and DateDeleted. Nothing was in it before.
+	6: func(doc map[string]any) error { return nil },
+	// Version 8 added the archive: IsArchived, DateArchived and DateChecked.
+	// Notes checked before that count from DateUpdated, see checkedAt.
+	7: func(doc map[string]any) error { return nil },
+}
+
+// boardDocument is the on-disk shape of a board
+type boardDocument struct {
+	SchemaVersion int `json:"schemaVersion"`
+	BoardState
+}
+
+// NewerSchemaError means the file was written by a newer kagoban than this one
+type NewerSchemaError struct {
+	Version int
+}
+
+func (e *NewerSchemaError) Error() string {
+	return fmt.Sprintf(
+		"the board was saved by a newer version of kagoban (schema version %d, this version reads up to %d). Update kagoban to open it",
+		e.Version, currentSchemaVersion,
+	)
+}
+
+func encodeBoard(state BoardState) ([]byte, error) {
+	return json.MarshalIndent(boardDocument{SchemaVersion: currentSchemaVersion, BoardState: state}, "", "    ")
+}
+
+// decodeBoard reads a board document of any known version, migrating it up to
+// currentSchemaVersion first.
+func decodeBoard(data []byte) (BoardState, error) {
+	var doc map[string]any
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return BoardState{}, err
+	}
+	if doc == nil {
+		// A bare null unmarshals without an error, and leaves nothing to migrate
+		return BoardState{}, errors.New("the board is null")
+	}
+
+	version := 0
+	if v, ok := doc["schemaVersion"]; ok {
+		number, ok := v.(float64)
+		if !ok || number != float64(int(number)) || number < 0 {
+			return BoardState{}, fmt.Errorf("schemaVersion %v is not a version number", v)
+		}
+		version = int(number)
+	}
+
+	if version > currentSchemaVersion {
+		return BoardState{}, &NewerSchemaError{Version: version}
+	}
+
+	for ; version < currentSchemaVersion; version++ {
+		migrate, ok := migrations[version]
+		if !ok {
+			return BoardState{}, fmt.Errorf("no migration from schema version %d", version)
+		}
+		if err := migrate(doc); err != nil {
+			return BoardState{}, fmt.Errorf("migrating from schema version %d: %w", version, err)
+		}
+	}
+	doc["schemaVersion"] = currentSchemaVersion
+
+	// Back through JSON to get the typed board out of the generic document
+	migrated, err := json.Marshal(doc)
+	if err != nil {
+		return BoardState{}, err
+	}
+	var board boardDocument
+	if err := json.Unmarshal(migrated, &board); err != nil {
+		return BoardState{}, err
+	}
+	board.RepairIDs()
+	return board.BoardState, nil
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDecodeBoard(t *testing.T) {
+	const sections = `"SectionData": [{"ID": 0, "Order": 0, "Name": "Inbox"}, {"ID": 1, "Order": 1, "Name": "Doing"}]`
+
+	tests := []struct {
+		name       string
+		data       string
+		want       string
+		wantIDs    []int // Of the notes, in the order of the file
+		wantNextID int
+		wantErr    string
+	}{
+		{
+			// Saved before schemaVersion and NextID, every note at ID 0
+			"version 0",
+			`{` + sections + `, "Notes": [{"Content": "a", "SectionID": 0}, {"Content": "b", "SectionID": 1}]}`,
+			"Inbox: a; Doing: b", []int{2, 3}, 4, "",
+		},
+		{
+			// Sections from a counter that restarted on every launch
+			"version 0 with sections sharing an ID",
+			`{"SectionData": [{"ID": 0, "Order": 0, "Name": "Inbox"}, {"ID": 0, "Order": 1, "Name": "Doing"}], "Notes": [{"ID": 0, "Content": "a", "SectionID": 0}]}`,
+			"Inbox: a; Doing", []int{2}, 3, "",
+		},
+		{
+			"version 1",
+			`{"schemaVersion": 1, ` + sections + `, "Notes": [{"ID": 4, "Content": "a"}, {"ID": 4, "Order": 1, "Content": "b"}]}`,
+			"Inbox: a b; Doing", []int{4, 5}, 6, "",
+		},
+		{
+			"version 2",
+			`{"schemaVersion": 2, ` + sections + `, "Notes": [{"ID": 4, "Content": "a", "SectionID": 1}], "NextID": 9}`,
+			"Inbox; Doing: a", []int{4}, 9, "",
+		},
+		{
+			"version 2 with NextID behind",
+			`{"schemaVersion": 2, ` + sections + `, "Notes": [{"ID": 4, "Content": "a"}], "NextID": 2}`,
+			"Inbox: a; Doing", []int{4}, 5, "",
+		},
+		{"newer version", `{"schemaVersion": 9}`, "", nil, 0, "newer version of kagoban"},
+		{"version as text", `{"schemaVersion": "2"}`, "", nil, 0, "not a version number"},
+		{"fractional version", `{"schemaVersion": 1.5}`, "", nil, 0, "not a version number"},
+		{"negative version", `{"schemaVersion": -1}`, "", nil, 0, "not a version number"},
+		{"not JSON", `Inbox: a`, "", nil, 0, "invalid character"},
+		{"null", `null`, "", nil, 0, "the board is null"},
+		{"wrong shape", `{"Notes": "a"}`, "", nil, 0, "cannot unmarshal"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeBoard([]byte(tt.data))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got %v, want an error saying %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if layout(&got) != tt.want {
+				t.Errorf("got %q, want %q", layout(&got), tt.want)
+			}
+			ids := []int{}
+			for _, n := range got.Notes {
+				ids = append(ids, n.ID)
+			}
+			if tt.wantIDs != nil && !slices.Equal(ids, tt.wantIDs) {
+				t.Errorf("note IDs are %v, want %v", ids, tt.wantIDs)
+			}
+			if got.NextID != tt.wantNextID {
+				t.Errorf("NextID is %d, want %d", got.NextID, tt.wantNextID)
+			}
+		})
+	}
+}
+
+func TestDecodeBoardNewerSchema(t *testing.T) {
+	_, err := decodeBoard([]byte(`{"schemaVersion": 9}`))
+	var newer *NewerSchemaError
+	if !errors.As(err, &newer) || newer.Version != 9 {
+		t.Errorf("got %v, want a NewerSchemaError for version 9", err)
+	}
+}
+
+func TestEncodeBoardRoundTrip(t *testing.T) {
+	want := testBoard()
+	for _, cmd := range []Command{
+		AddNoteCmd{SectionID: inbox, Content: "a"},
+		TagNoteCmd{SectionID: inbox, Order: 0, Tags: []string{"docs"}},
+		AddNoteCmd{SectionID: doing, Content: "b"},
+		AddNoteCmd{SectionID: doing, Content: "c"},
+		DeleteNoteCmd{SectionID: doing, Order: 0},
+		SaveViewCmd{Name: "docs", Query: "#docs"},
+	} {
+		if err := want.Apply(cmd, testNow); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	data, err := encodeBoard(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"schemaVersion": 8`) {
+		t.Errorf("no schemaVersion in %s", data)
+	}
+	got, err := decodeBoard(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameBoard(t, &got, &want)
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// fuzzyMatch tells whether every rune of query shows up in text in the same
+// order, ignoring case. It returns the rune positions in text that matched,
+// the first place each one fits.
+func fuzzyMatch(query string, text string) ([]int, bool) {
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return nil, false
+	}
+	positions := []int{}
+	i := 0
+	for pos, r := range []rune(text) {
+		if i < len(q) && unicode.ToLower(r) == q[i] {
+			positions = append(positions, pos)
+			i++
+		}
+	}
+	return positions, i == len(q)
+}
+
+// SearchMatch says where the search query matched a note's title. The
+// description has to contain the query as it is, in a long text almost any
+// query would match fuzzily. A match there has no positions to highlight.
+func (m ProgramModel) SearchMatch(n *Note) ([]int, bool) {
+	query := m.UIControl.Search
+	if query == "" {
+		return nil, false
+	}
+	if positions, ok := fuzzyMatch(strings.ReplaceAll(query, " ", ""), n.Content); ok {
+		return positions, true
+	}
+	return nil, strings.Contains(strings.ToLower(n.Description), strings.ToLower(query))
+}
+
+// SearchHits lists the notes on screen that match the search, in the order
+// they are on the board: left to right, then top to bottom
+func (m ProgramModel) SearchHits() []*Note {
+	hits := []*Note{}
+	if m.UIControl.Search == "" {
+		return hits
+	}
+	for order := range len(m.SectionData) {
+		notes, _ := FindNotesBySectionOrder(m, order)
+		for _, n := range notes {
+			if _, ok := m.SearchMatch(n); ok {
+				hits = append(hits, n)
+			}
+		}
+	}
+	return hits
+}
+
+// OpenSearch starts typing a search, the board follows every key press
+func (m *ProgramModel) OpenSearch() tea.Cmd {
+	return m.OpenTextInput(SearchOperation, "Search", "Type to search all notes", m.UIControl.Search)
+}
+
+// UpdateSearch runs the search again after the query changed
+func (m *ProgramModel) UpdateSearch(query string) {
+	m.UIControl.Search = query
+	m.JumpToHit(0)
+}
+
+// JumpToHit moves the cursor to the next match after the one under the cursor
+// (step 1), the previous one (step -1) or stays when the cursor is on a
+// match already (step 0). It wraps around the board.
+func (m *ProgramModel) JumpToHit(step int) {
+	hits := m.SearchHits()
+	if m.UIControl.Search == "" {
+		return
+	}
+	if len(hits) == 0 {
+		m.StatusText = "No match for " + m.UIControl.Search
+		return
+	}
+
+	// Where the cursor is among all notes on screen, and which hit comes at or after it
+	current := m.cursorRank()
+	idx := len(hits)
+	for i, hit := range hits {
+		if m.rank(hit) >= current {
+			idx = i
+			break
+		}
+	}
+
+	onHit := idx < len(hits) && m.rank(hits[idx]) == current
+	switch {
+	case step > 0 && onHit:
+		idx++
+	case step < 0:
+		idx--
+	}
+	idx = (idx%len(hits) + len(hits)) % len(hits)
+
+	m.SelectNote(hits[idx].ID)
+	m.StatusText = fmt.Sprintf("Match %d of %d for %s", idx+1, len(hits), m.UIControl.Search)
+}
+
+// rank is the place of a note on the board when read section by section
+func (m ProgramModel) rank(n *Note) int {
+	section, _ := m.SectionByID(n.SectionID)
+	for i, other := range m.UIControl.DisplayOrder[n.SectionID] {
+		if other == n {
+			return section.Order*len(m.Notes) + i
+		}
+	}
+	return -1
+}
+
+func (m ProgramModel) cursorRank() int {
+	return m.UIControl.SectionCursor*len(m.Notes) + m.UIControl.RowCursor
+}
+
+// highlight renders the runes of s at positions with searchMatchStyle. The
+// rest is rendered with base, the highlight would otherwise reset the colors
+// of the card around it.
+func highlight(s string, positions []int, base lg.Style) string {
+	if len(positions) == 0 {
+		return s
+	}
+	match := searchMatchStyle.Inherit(base)
+	out := strings.Builder{}
+	next := 0
+	for pos, r := range []rune(s) {
+		if next < len(positions) && positions[next] == pos {
+			out.WriteString(match.Render(string(r)))
+			next++
+		} else {
+			out.WriteString(base.Render(string(r)))
+		}
+	}
+	return out.String()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		query, text string
+		want        []int
+		ok          bool
+	}{
+		{"", "anything", nil, false},
+		{"ap", "apple", []int{0, 1}, true},
+		{"ap", "Grape", []int{2, 3}, true},
+		{"GRP", "grape", []int{0, 1, 3}, true},
+		{"pa", "apple", nil, false},
+		{"é", "café", []int{3}, true},
+		{"apples", "apple", nil, false},
+	}
+	for _, tt := range tests {
+		got, ok := fuzzyMatch(tt.query, tt.text)
+		if ok != tt.ok || (ok && !slices.Equal(got, tt.want)) {
+			t.Errorf("fuzzyMatch(%q, %q) is %v %v, want %v %v", tt.query, tt.text, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestSearchJumps(t *testing.T) {
+	tests := []struct {
+		name   string
+		keys   []string
+		want   string // Title under the cursor
+		status string
+	}{
+		{"first match", []string{"/", "+ap"}, "apple", "Match 1 of 3 for ap"},
+		{"next", []string{"/", "+ap", "enter", "n"}, "grape", "Match 2 of 3 for ap"},
+		{"wraps around", []string{"/", "+ap", "enter", "n", "n", "n"}, "apple", "Match 1 of 3 for ap"},
+		{"previous wraps around", []string{"/", "+ap", "enter", "N"}, "papaya", "Match 3 of 3 for ap"},
+		{"description", []string{"/", "+seeds", "enter"}, "papaya", "Match 1 of 1 for seeds"},
+		{"no match", []string{"/", "+xyz", "enter"}, "apple", "No match for xyz"},
+		{"esc drops the search", []string{"/", "+ap", "esc", "n"}, "apple", "Press / to search"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := testModel(t)
+			for _, title := range []string{"apple", "banana", "grape", "papaya"} {
+				m.Apply(AddNoteCmd{SectionID: 0, Content: title})
+			}
+			m.Apply(DescribeNoteCmd{SectionID: 0, Order: 3, Description: "Black seeds"})
+			m.SelectNote(m.NoteAt(0, 0).ID)
+
+			m = play(m, tt.keys...)
+			if note := m.SelectedNote(); note == nil || note.Content != tt.want {
+				t.Errorf("cursor on %+v, want %s", note, tt.want)
+			}
+			if m.StatusText != tt.status {
+				t.Errorf("status is %q, want %q", m.StatusText, tt.status)
+			}
+		})
+	}
+}
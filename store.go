@@ -0,0 +1,232 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// How often file stores check the board file for changes made by someone else
+const watchInterval = time.Second
+
+// BoardState is the part of the program that gets persisted
+type BoardState struct {
+	SectionData []Section
+	Notes       []*Note
+	NextID      int         // Next ID to hand out, see NewID
+	Views       []SavedView // Named queries, see views.go
+
+	DeletedSections []Section // Sections in the trash, see trash.go
+}
+
+// Store is where a board lives. Update only talks to this interface so the
+// JSON file can be swapped for something else (SQLite, a shared directory,
+// memory in tests) without touching the UI code.
+type Store interface {
+	Load() (BoardState, error)
+	Save(state BoardState) error
+	// Watch returns a channel that receives a value every time the board is
+	// changed outside of this Store. A nil channel means the store can't watch.
+	Watch() (<-chan struct{}, error)
+	// Close stops the watcher and closes the channel returned by Watch
+	Close() error
+}
+
+// storeChangedMsg is sent to Update when the Store reports an outside change
+type storeChangedMsg struct{}
+
+func waitForStoreChange(ch <-chan struct{}) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if _, ok := <-ch; !ok {
+			return nil
+		}
+		return storeChangedMsg{}
+	}
+}
+
+// CorruptBoardError means the board file is there but can't be read back
+type CorruptBoardError struct {
+	Path string
+	Err  error
+}
+
+func (e *CorruptBoardError) Error() string {
+	return fmt.Sprintf("%s is corrupt: %v", e.Path, e.Err)
+}
+
+func (e *CorruptBoardError) Unwrap() error { return e.Err }
+
+// JSONFileStore keeps the whole board in a single JSON file
+type JSONFileStore struct {
+	Path    string
+	Backups int // How many old versions of the file to keep, see backup.go
+
+	watcher fileWatcher
+}
+
+func NewJSONFileStore(path string) *JSONFileStore {
+	return &JSONFileStore{Path: path, Backups: defaultBackups}
+}
+
+func (s *JSONFileStore) Load() (BoardState, error) {
+	jsonData, err := os.ReadFile(s.Path)
+	if err != nil {
+		return BoardState{}, err
+	}
+
+	state, err := decodeBoard(jsonData)
+	var newer *NewerSchemaError
+	if errors.As(err, &newer) {
+		// Nothing wrong with the file, we are just too old for it
+		return BoardState{}, err
+	} else if err != nil {
+		return BoardState{}, &CorruptBoardError{Path: s.Path, Err: err}
+	}
+
+	s.rememberModTime()
+	return state, nil
+}
+
+func (s *JSONFileStore) Save(state BoardState) error {
+	jsonData, err := encodeBoard(state)
+	if err != nil {
+		return err
+	}
+
+	// First save of a new board, the directory might not be there yet
+	if err := os.MkdirAll(filepath.Dir(s.Path), 0700); err != nil {
+		return err
+	}
+
+	if err := s.backup(); err != nil {
+		return err
+	}
+
+	// Never write over the board in place, a crash halfway would leave it truncated
+	if err := writeFileAtomic(s.Path, jsonData, 0600); err != nil {
+		return err
+	}
+
+	s.rememberModTime()
+	return nil
+}
+
+// Watch polls the file's mod time. Our own writes are not reported because
+// Load and Save remember the mod time they left behind.
+func (s *JSONFileStore) Watch() (<-chan struct{}, error) {
+	return s.watcher.watch(s.Path), nil
+}
+
+func (s *JSONFileStore) Close() error {
+	s.watcher.close()
+	return nil
+}
+
+func (s *JSONFileStore) rememberModTime() {
+	s.watcher.remember(s.Path)
+}
+
+// fileWatcher reports changes to a file by polling its mod time
+type fileWatcher struct {
+	mu      sync.Mutex
+	modTime time.Time // mod time of the file as we last read or wrote it
+	done    chan struct{}
+}
+
+func (w *fileWatcher) watch(path string) <-chan struct{} {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done != nil {
+		close(w.done)
+	}
+	w.done = make(chan struct{})
+	done := w.done
+
+	ch := make(chan struct{}, 1)
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				info, err := os.Stat(path)
+				if err != nil {
+					continue
+				}
+
+				w.mu.Lock()
+				changed := !info.ModTime().Equal(w.modTime)
+				w.modTime = info.ModTime()
+				w.mu.Unlock()
+
+				if changed {
+					select {
+					case ch <- struct{}{}:
+					default: // a change is already pending
+					}
+				}
+			}
+		}
+	}()
+
+	return ch
+}
+
+func (w *fileWatcher) close() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.done != nil {
+		close(w.done)
+		w.done = nil
+	}
+}
+
+// remember records the current mod time of path as one we caused ourselves
+func (w *fileWatcher) remember(path string) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return
+	}
+	w.mu.Lock()
+	w.modTime = info.ModTime()
+	w.mu.Unlock()
+}
+
+// MemoryStore keeps the board in memory only. Handy for tests and demos.
+type MemoryStore struct {
+	mu    sync.Mutex
+	state BoardState
+}
+
+func NewMemoryStore(state BoardState) *MemoryStore {
+	return &MemoryStore{state: CloneBoardState(state)}
+}
+
+func (s *MemoryStore) Load() (BoardState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return CloneBoardState(s.state), nil
+}
+
+func (s *MemoryStore) Save(state BoardState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = CloneBoardState(state)
+	return nil
+}
+
+func (s *MemoryStore) Watch() (<-chan struct{}, error) { return nil, nil }
+
+func (s *MemoryStore) Close() error { return nil }
@@ -6,6 +6,8 @@ const (
 	CardBorderColor    = "#FFBF00"
 	CardBackgroudColor = "#ffd75f"
 	ForegroundColor    = "#000000"
+	OverdueColor       = "#ff8787"
+	DueSoonColor       = "#ffaf5f"
 )
 
 var systemStyle = lipgloss.NewStyle().
@@ -21,5 +23,38 @@ var cardStyle = lipgloss.NewStyle().
 	Padding(1, 2, 1, 2).
 	Height(5).Width(20)
 
+// Cards of notes that are past or close to their due date
+var overdueCardStyle = cardStyle.
+	BorderForeground(lipgloss.Color(OverdueColor)).
+	Background(lipgloss.Color(OverdueColor))
+
+var dueSoonCardStyle = cardStyle.
+	BorderForeground(lipgloss.Color(DueSoonColor)).
+	Background(lipgloss.Color(DueSoonColor))
+
+var searchMatchStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+
+var dueBadgeStyle = lipgloss.NewStyle().Bold(true)
+
 var sectionHeaderStyle = lipgloss.NewStyle().Bold(true).
 	Background(lipgloss.Color(CardBackgroudColor)).Padding(0, 1).Foreground(lipgloss.Color(ForegroundColor))
+
+var boardTitleStyle = lipgloss.NewStyle().Bold(true).Underline(true)
+
+var boardPickerStyle = lipgloss.NewStyle().
+	BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color(CardBorderColor)).
+	Padding(1, 2)
+
+var boardPickerTitleStyle = lipgloss.NewStyle().Bold(true)
+
+var boardPickerCursorStyle = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color(CardBorderColor))
+
+var scrollHintStyle = lipgloss.NewStyle().Faint(true)
+
+var dirtyMarkStyle = lipgloss.NewStyle().Foreground(lipgloss.Color(CardBorderColor))
+
+var dialogStyle = lipgloss.NewStyle().
+	BorderStyle(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color(CardBorderColor)).
+	Padding(1, 2)
+
+var tagChipStyle = lipgloss.NewStyle().Padding(0, 1)
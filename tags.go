@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+// parseTags turns "bug, #ui later" into [bug ui later]. Tags are lower case
+// and a note never has the same tag twice.
+func parseTags(s string) []string {
+	tags := []string{}
+	for _, field := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == ' ' }) {
+		tag := strings.ToLower(strings.TrimLeft(field, "#"))
+		if tag != "" && !slices.Contains(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// AllTags lists every tag used on the board, sorted
+func (b *BoardState) AllTags() []string {
+	tags := []string{}
+	for _, n := range b.Notes {
+		if !b.IsActive(n) {
+			continue
+		}
+		for _, tag := range n.Tags {
+			if !slices.Contains(tags, tag) {
+				tags = append(tags, tag)
+			}
+		}
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// HasTags tells whether the note carries every one of tags
+func (n *Note) HasTags(tags []string) bool {
+	for _, tag := range tags {
+		if !slices.Contains(n.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// tagColor is the background of a tag's chip. Like randomHex but the same
+// tag always gets the same color.
+func tagColor(tag string) (r, g, b byte) {
+	h := fnv.New32a()
+	h.Write([]byte(tag))
+	sum := h.Sum32()
+	return byte(sum >> 16), byte(sum >> 8), byte(sum)
+}
+
+func tagChip(tag string) string {
+	r, g, b := tagColor(tag)
+	fg := "#ffffff"
+	// Dark text on light chips
+	if int(r)*299+int(g)*587+int(b)*114 > 128*1000 {
+		fg = ForegroundColor
+	}
+	bg := fmt.Sprintf("#%02x%02x%02x", r, g, b)
+	return tagChipStyle.Background(lg.Color(bg)).Foreground(lg.Color(fg)).Render(tag)
+}
+
+// tagChips renders as many chips as fit in width and counts the rest
+func tagChips(tags []string, width int) string {
+	line := ""
+	for i, tag := range tags {
+		chip := tagChip(tag)
+		// Leave room for the " +N"
+		if line != "" && lg.Width(line)+1+lg.Width(chip) > width-4 {
+			return line + fmt.Sprintf(" +%d", len(tags)-i)
+		}
+		if line != "" {
+			line += " "
+		}
+		line += chip
+	}
+	return line
+}
+
+// OpenTagEditor asks for the tags of the note under the cursor
+func (m *ProgramModel) OpenTagEditor() tea.Cmd {
+	note := m.SelectedNote()
+	if note == nil {
+		return nil
+	}
+	return m.OpenTextInputLimit(TagNoteOperation, "Which tags does this note have?", "e.g. bug, ui, later", strings.Join(note.Tags, " "), noteCharLimit)
+}
+
+// TagPicker is the state of the tag filter overlay
+type TagPicker struct {
+	Tags     []string
+	Selected []string
+	Cursor   int
+}
+
+func (m *ProgramModel) OpenTagPicker() {
+	tags := m.AllTags()
+	if len(tags) == 0 {
+		m.StatusText = "No note has a tag yet, press t to tag one"
+		return
+	}
+	m.UIControl.Overlay = TagFilterOverlay
+	m.UIControl.TagPicker = TagPicker{
+		Tags:     tags,
+		Selected: slices.Clone(m.UIControl.TagFilter),
+	}
+}
+
+func (m ProgramModel) UpdateTagPicker(msg tea.KeyMsg) (ProgramModel, tea.Cmd) {
+	picker := &m.UIControl.TagPicker
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m.Quit()
+
+	case "esc", "q":
+		m.UIControl.Overlay = NoOverlay
+
+	case "up", "k":
+		if picker.Cursor > 0 {
+			picker.Cursor--
+		}
+
+	case "down", "j":
+		if picker.Cursor < len(picker.Tags)-1 {
+			picker.Cursor++
+		}
+
+	case " ", "x":
+		tag := picker.Tags[picker.Cursor]
+		if idx := slices.Index(picker.Selected, tag); idx != -1 {
+			picker.Selected = slices.Delete(picker.Selected, idx, idx+1)
+		} else {
+			picker.Selected = append(picker.Selected, tag)
+		}
+
+	case "c":
+		picker.Selected = nil
+
+	case "enter":
+		m.UIControl.Overlay = NoOverlay
+		m.SetTagFilter(picker.Selected)
+	}
+
+	return m, nil
+}
+
+// SetTagFilter hides every note without all of tags, nil shows them all again
+func (m *ProgramModel) SetTagFilter(tags []string) {
+	selected := m.SelectedNote()
+	m.UIControl.TagFilter = tags
+	m.reselect(selected)
+}
+
+func (m ProgramModel) TagPickerView() string {
+	picker := m.UIControl.TagPicker
+	text := boardPickerTitleStyle.Render("Show notes tagged with") + "\n\n"
+
+	for i, tag := range picker.Tags {
+		cursor := "  "
+		if i == picker.Cursor {
+			cursor = boardPickerCursorStyle.Render("> ")
+		}
+		checked := "[ ]"
+		if slices.Contains(picker.Selected, tag) {
+			checked = "[x]"
+		}
+		text += fmt.Sprintf("%s%s %s\n", cursor, checked, tagChip(tag))
+	}
+
+	text += "\nspace: select  c: clear  enter: apply  esc: cancel"
+	return boardPickerStyle.Render(text)
+}
+
+// tagFilterTitle is shown next to the board title while the filter is on
+func (m ProgramModel) tagFilterTitle() string {
+	if len(m.UIControl.TagFilter) == 0 {
+		return ""
+	}
+	chips := []string{}
+	for _, tag := range m.UIControl.TagFilter {
+		chips = append(chips, tagChip(tag))
+	}
+	return "  " + scrollHintStyle.Render("filter:") + " " + strings.Join(chips, " ")
+}
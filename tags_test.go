@@ -0,0 +1,47 @@
+package main
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestParseTags(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"", []string{}},
+		{"bug", []string{"bug"}},
+		{"bug, #ui later", []string{"bug", "ui", "later"}},
+		{"Bug bug #BUG", []string{"bug"}},
+		{" , # ,", []string{}},
+	}
+	for _, tt := range tests {
+		if got := parseTags(tt.in); !slices.Equal(got, tt.want) {
+			t.Errorf("parseTags(%q) is %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestAllTagsAndFilter(t *testing.T) {
+	b := testBoard()
+	b.Apply(AddNoteCmd{SectionID: inbox, Content: "a"}, testNow)
+	b.Apply(AddNoteCmd{SectionID: doing, Content: "b"}, testNow)
+	b.Apply(TagNoteCmd{SectionID: inbox, Order: 0, Tags: []string{"ui", "bug"}}, testNow)
+	b.Apply(TagNoteCmd{SectionID: doing, Order: 0, Tags: []string{"bug"}}, testNow)
+
+	if got := b.AllTags(); !slices.Equal(got, []string{"bug", "ui"}) {
+		t.Errorf("AllTags is %v", got)
+	}
+	b.Apply(DeleteSectionCmd{SectionID: inbox}, testNow)
+	if got := b.AllTags(); !slices.Equal(got, []string{"bug"}) {
+		t.Errorf("AllTags is %v with Inbox in the trash", got)
+	}
+	filter := []string{"bug", "ui"}
+	if !b.Notes[0].HasTags(filter) || b.NoteAt(doing, 0).HasTags(filter) {
+		t.Error("the filter needs every one of its tags")
+	}
+	if !b.NoteAt(doing, 0).HasTags(nil) {
+		t.Error("no filter hides a note")
+	}
+}
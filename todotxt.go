@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+	"time"
+)
+
+// todo.txt (http://todotxt.org) keeps one task per line:
+//
+//	x 2026-10-18 2026-10-01 Write the docs +Doing @docs due:2026-10-20
+//
+// A leading x and the completion date mark a done task, followed by the
+// creation date. The +project is the section, @contexts are tags and due: is
+// the due date. A (A) priority stays at the start of the note's title.
+// Descriptions don't fit on one line and are left out of exports.
+
+const todoTxtFormat = "todo.txt"
+
+func exportTodoTxt(w io.Writer, e exportedBoard) error {
+	text := ""
+	for _, s := range e.Sections {
+		for _, n := range s.Notes {
+			parts := []string{}
+			content := n.Content
+			if n.Checked {
+				checkedAt := n.CheckedAt
+				if checkedAt.IsZero() {
+					checkedAt = n.Updated
+				}
+				parts = append(parts, "x", todoDate(checkedAt))
+			} else if priority, rest, ok := todoPriority(content); ok {
+				parts = append(parts, priority)
+				content = rest
+			}
+			if !n.Created.IsZero() {
+				parts = append(parts, todoDate(n.Created))
+			}
+			parts = append(parts, content, "+"+strings.Join(strings.Fields(s.Name), "-"))
+			for _, tag := range n.Tags {
+				parts = append(parts, "@"+tag)
+			}
+			if !n.Due.IsZero() {
+				parts = append(parts, "due:"+todoDate(n.Due))
+			}
+			text += strings.Join(parts, " ") + "\n"
+		}
+	}
+	_, err := io.WriteString(w, text)
+	return err
+}
+
+// parseTodoTxt reads a todo.txt file, one note per line. Tasks without a
+// +project go to the first section.
+func parseTodoTxt(r io.Reader) (ImportedBoard, error) {
+	board := ImportedBoard{}
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		words := strings.Fields(scanner.Text())
+		if len(words) == 0 {
+			continue
+		}
+
+		note := ImportedNote{}
+		if words[0] == "x" {
+			note.Checked = true
+			words = words[1:]
+			if len(words) > 0 {
+				if at, ok := parseTodoDate(words[0]); ok {
+					note.CheckedAt = at
+					words = words[1:]
+				}
+			}
+		}
+		priority := ""
+		if len(words) > 0 && !note.Checked {
+			if p, _, ok := todoPriority(words[0] + " "); ok {
+				priority = p
+				words = words[1:]
+			}
+		}
+		if len(words) > 0 {
+			if at, ok := parseTodoDate(words[0]); ok {
+				note.Created = at
+				words = words[1:]
+			}
+		}
+
+		project := ""
+		title := []string{}
+		contexts := []string{}
+		for _, word := range words {
+			switch {
+			case strings.HasPrefix(word, "+") && len(word) > 1 && project == "":
+				project = word[1:]
+			case strings.HasPrefix(word, "@") && len(word) > 1:
+				contexts = append(contexts, word[1:])
+			case strings.HasPrefix(word, "due:"):
+				if due, ok := parseTodoDate(word[len("due:"):]); ok {
+					note.Due = due
+					continue
+				}
+				title = append(title, word)
+			default:
+				title = append(title, word)
+			}
+		}
+		if len(title) == 0 {
+			continue
+		}
+		if priority != "" {
+			title = append([]string{priority}, title...)
+		}
+		note.Content = strings.Join(title, " ")
+		note.Tags = parseTags(strings.Join(contexts, " "))
+
+		idx := slices.IndexFunc(board.Sections, func(s ImportedSection) bool { return s.Name == project })
+		if idx == -1 {
+			board.Sections = append(board.Sections, ImportedSection{Name: project})
+			idx = len(board.Sections) - 1
+		}
+		board.Sections[idx].Notes = append(board.Sections[idx].Notes, note)
+	}
+	if err := scanner.Err(); err != nil {
+		return ImportedBoard{}, err
+	}
+	if len(board.Sections) == 0 {
+		return ImportedBoard{}, errors.New("no tasks found")
+	}
+	return board, nil
+}
+
+// todoPriority splits a "(A) " priority off the start of s
+func todoPriority(s string) (string, string, bool) {
+	if len(s) < 4 || s[0] != '(' || s[1] < 'A' || s[1] > 'Z' || s[2] != ')' || s[3] != ' ' {
+		return "", s, false
+	}
+	return s[:3], strings.TrimSpace(s[4:]), true
+}
+
+func todoDate(t time.Time) string {
+	return t.Local().Format(time.DateOnly)
+}
+
+func parseTodoDate(s string) (time.Time, bool) {
+	t, err := time.ParseInLocation(time.DateOnly, s, time.Local)
+	return t, err == nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseTodoTxt(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 10, d, 0, 0, 0, 0, time.Local) }
+
+	tests := []struct {
+		name    string
+		input   string
+		want    ImportedBoard
+		wantErr bool
+	}{
+		{
+			"tasks",
+			"x 2026-10-18 2026-10-01 Write the docs +Doing @docs due:2026-10-20\n" +
+				"(A) 2026-10-02 Call the bank @phone @Errands\n" +
+				"\n" +
+				"(B) Fix it +Doing +Later due:soon\n" +
+				"x Done thing\n" +
+				"x (C) Checked with a priority\n" +
+				"+Doing @only\n",
+			ImportedBoard{Sections: []ImportedSection{
+				{Name: "Doing", Notes: []ImportedNote{
+					{Content: "Write the docs", Tags: []string{"docs"}, Checked: true, Due: day(20), Created: day(1), CheckedAt: day(18)},
+					{Content: "(B) Fix it +Later due:soon", Tags: []string{}},
+				}},
+				{Notes: []ImportedNote{
+					{Content: "(A) Call the bank", Tags: []string{"phone", "errands"}, Created: day(2)},
+					{Content: "Done thing", Tags: []string{}, Checked: true},
+					{Content: "(C) Checked with a priority", Tags: []string{}, Checked: true},
+				}},
+			}},
+			false,
+		},
+		{"no tasks", "\n  \n+Doing\n", ImportedBoard{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkImport(t, parseTodoTxt, tt.input, tt.want, tt.wantErr)
+		})
+	}
+}
+
+// Exporting and importing again keeps the sections, titles, priorities, tags and dates
+func TestTodoTxtRoundTrip(t *testing.T) {
+	want := testBoard()
+	for _, cmd := range []Command{
+		AddNoteCmd{SectionID: inbox, Content: "(A) Call the bank"},
+		TagNoteCmd{SectionID: inbox, Order: 0, Tags: []string{"phone"}},
+		DueNoteCmd{SectionID: inbox, Order: 0, DueDate: time.Date(2026, 10, 20, 0, 0, 0, 0, time.Local)},
+		AddNoteCmd{SectionID: doing, Content: "Write the docs"},
+		ToggleNoteCmd{SectionID: doing, Order: 0},
+		RenameSectionCmd{SectionID: doing, Name: "In Progress"},
+	} {
+		if err := want.Apply(cmd, testNow); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var text strings.Builder
+	if err := exportTodoTxt(&text, newExport("board", &want, func(n *Note) bool { return true }, testNow)); err != nil {
+		t.Fatal(err)
+	}
+	imported, err := parseTodoTxt(strings.NewReader(text.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// "In-Progress" comes back into "In Progress"
+	got := testBoard()
+	got.Apply(RenameSectionCmd{SectionID: doing, Name: "In Progress"}, testNow)
+	if err := got.Apply(ImportBoardCmd{Board: imported}, testNow); err != nil {
+		t.Fatal(err)
+	}
+	if layout(&got) != layout(&want) {
+		t.Fatalf("got  %q\nwant %q", layout(&got), layout(&want))
+	}
+	for i := range want.Notes {
+		g, w := got.Notes[i], want.Notes[i]
+		if !slices.Equal(g.Tags, w.Tags) || !g.DueDate.Equal(w.DueDate) || !g.DateCreated.Equal(startOfDay(w.DateCreated)) {
+			t.Errorf("got %+v, want %+v", *g, *w)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Deleting a note only sets IsDeleted, deleting a section moves it to
+// BoardState.DeletedSections with its notes left as they are. Both stay in
+// the trash until they are restored or purged, by hand or after PurgeAfter days.
+
+// TrashItem is a line of the trash view, either a note or a section
+type TrashItem struct {
+	Note    *Note
+	Section *Section
+}
+
+func (t TrashItem) DeletedAt() time.Time {
+	if t.Note != nil {
+		return t.Note.DateDeleted
+	}
+	return t.Section.DateDeleted
+}
+
+// Trash lists everything in the trash, the latest deleted first. Notes that
+// went with their section are listed under the section, not one by one.
+func (b *BoardState) Trash() []TrashItem {
+	items := []TrashItem{}
+	for i := range b.DeletedSections {
+		items = append(items, TrashItem{Section: &b.DeletedSections[i]})
+	}
+	for _, n := range b.Notes {
+		if n.IsDeleted {
+			items = append(items, TrashItem{Note: n})
+		}
+	}
+	slices.SortStableFunc(items, func(a, b TrashItem) int { return b.DeletedAt().Compare(a.DeletedAt()) })
+	return items
+}
+
+// AutoPurge empties the trash of everything older than PurgeAfter days
+func (m *ProgramModel) AutoPurge() {
+	if m.PurgeAfter <= 0 {
+		return
+	}
+	before := time.Now().AddDate(0, 0, -m.PurgeAfter)
+	expired := 0
+	for _, item := range m.Trash() {
+		if item.DeletedAt().Before(before) {
+			expired++
+		}
+	}
+	if expired == 0 {
+		return
+	}
+	if m.applyOrReport(PurgeTrashCmd{Before: before}) {
+		m.StatusText = fmt.Sprintf("Purged %s deleted more than %d days ago", plural(expired, "item"), m.PurgeAfter)
+	}
+}
+
+func (m *ProgramModel) OpenTrash() {
+	m.UIControl.Overlay = TrashOverlay
+	m.UIControl.TrashCursor = 0
+}
+
+func (m ProgramModel) UpdateTrash(msg tea.KeyMsg) (ProgramModel, tea.Cmd) {
+	items := m.Trash()
+	cursor := &m.UIControl.TrashCursor
+
+	var item TrashItem
+	selected := *cursor < len(items)
+	if selected {
+		item = items[*cursor]
+	}
+
+	switch msg.String() {
+	case "ctrl+c":
+		return m.Quit()
+
+	case "esc", "q", "ctrl+t":
+		m.UIControl.Overlay = NoOverlay
+
+	case "up", "k":
+		if *cursor > 0 {
+			*cursor--
+		}
+
+	case "down", "j":
+		if *cursor < len(items)-1 {
+			*cursor++
+		}
+
+	case "r", "enter":
+		if !selected {
+			break
+		}
+		if item.Note != nil {
+			m.applyOrReport(RestoreNoteCmd{ID: item.Note.ID})
+		} else {
+			m.applyOrReport(RestoreSectionCmd{ID: item.Section.ID})
+		}
+
+	case "x":
+		if !selected {
+			break
+		}
+		var purge Command
+		var name string
+		if item.Section != nil {
+			purge = PurgeSectionCmd{ID: item.Section.ID}
+			name = "section " + item.Section.Name
+		} else {
+			purge = PurgeNoteCmd{ID: item.Note.ID}
+			name = item.Note.Content
+		}
+		return m.Confirm(ConfirmPurge, ConfirmDialog{
+			Message:  "Delete " + name + " for good?",
+			YesLabel: "delete",
+			Yes: func(m ProgramModel) (ProgramModel, tea.Cmd) {
+				m.applyOrReport(purge)
+				m.UIControl.TrashCursor = clamp(0, m.UIControl.TrashCursor, len(m.Trash())-1)
+				return m, nil
+			},
+		})
+
+	case "X":
+		if len(items) == 0 {
+			break
+		}
+		return m.Confirm(ConfirmPurge, ConfirmDialog{
+			Message:  fmt.Sprintf("Delete all %s in the trash for good?", plural(len(items), "item")),
+			YesLabel: "empty the trash",
+			Yes: func(m ProgramModel) (ProgramModel, tea.Cmd) {
+				m.applyOrReport(PurgeTrashCmd{})
+				m.UIControl.TrashCursor = 0
+				return m, nil
+			},
+		})
+
+	case "u":
+		if !m.Undo() {
+			m.StatusText = "Nothing to undo"
+		}
+	}
+
+	*cursor = clamp(0, *cursor, len(m.Trash())-1)
+	return m, nil
+}
+
+func (m ProgramModel) TrashView() string {
+	items := m.Trash()
+	now := time.Now()
+	text := boardPickerTitleStyle.Render("Trash") + "\n\n"
+
+	if len(items) == 0 {
+		text += "The trash is empty\n"
+	}
+	for i, item := range items {
+		cursor := "  "
+		if i == m.UIControl.TrashCursor {
+			cursor = boardPickerCursorStyle.Render("> ")
+		}
+
+		line := ""
+		if item.Note != nil {
+			section, ok := m.SectionByID(item.Note.SectionID)
+			from := section.Name
+			if !ok {
+				from = "a deleted section"
+			}
+			line = fmt.Sprintf("%s  %s", item.Note.Content, scrollHintStyle.Render("from "+from))
+		} else {
+			notes := 0
+			for _, n := range m.Notes {
+				if n.SectionID == item.Section.ID && !n.IsDeleted {
+					notes++
+				}
+			}
+			line = fmt.Sprintf("Section %s  %s", item.Section.Name, scrollHintStyle.Render(plural(notes, "note")))
+		}
+		text += fmt.Sprintf("%s%s  %s\n", cursor, line, scrollHintStyle.Render(relativeTime(item.DeletedAt(), now)))
+	}
+
+	text += "\nr: restore  x: delete for good  X: empty the trash  u: undo  esc: close"
+	return boardPickerStyle.Render(text)
+}
@@ -0,0 +1,33 @@
+package main
+
+import "testing"
+
+func TestTrashPurge(t *testing.T) {
+	tests := []struct {
+		name string
+		keys []string // Puts one thing in the trash
+	}{
+		{"note", []string{"a", "+one", "enter", "d"}},
+		{"section", []string{"A", "+Later", "enter", "a", "+one", "enter", "D", "y"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := play(testModel(t), tt.keys...)
+			if len(m.Trash()) != 1 {
+				t.Fatalf("%d items in the trash", len(m.Trash()))
+			}
+
+			m = press(m, "ctrl+t", "x")
+			if !m.UIControl.IsDialogOpened {
+				t.Fatal("x didn't ask first")
+			}
+			m = press(m, "y")
+			if len(m.Trash()) != 0 {
+				t.Errorf("%d items left in the trash", len(m.Trash()))
+			}
+			if len(m.Notes) != 0 {
+				t.Errorf("%d notes left on the board", len(m.Notes))
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// trelloBoard is the part of a Trello board export (Menu > Print, export and
+// share > Export as JSON) that kagoban can use
+type trelloBoard struct {
+	Lists      []trelloList      `json:"lists"`
+	Cards      []trelloCard      `json:"cards"`
+	Checklists []trelloChecklist `json:"checklists"`
+}
+
+type trelloList struct {
+	ID     string  `json:"id"`
+	Name   string  `json:"name"`
+	Closed bool    `json:"closed"`
+	Pos    float64 `json:"pos"`
+}
+
+type trelloCard struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Desc        string     `json:"desc"`
+	Closed      bool       `json:"closed"`
+	IDList      string     `json:"idList"`
+	Pos         float64    `json:"pos"`
+	Due         *time.Time `json:"due"`
+	DueComplete bool       `json:"dueComplete"`
+	Labels      []struct {
+		Name  string `json:"name"`
+		Color string `json:"color"`
+	} `json:"labels"`
+}
+
+type trelloChecklist struct {
+	IDCard     string            `json:"idCard"`
+	Name       string            `json:"name"`
+	Pos        float64           `json:"pos"`
+	CheckItems []trelloCheckItem `json:"checkItems"`
+}
+
+type trelloCheckItem struct {
+	Name  string  `json:"name"`
+	State string  `json:"state"` // complete or incomplete
+	Pos   float64 `json:"pos"`
+}
+
+// parseTrello maps open lists to sections and open cards to notes. A card is
+// checked when its due date is marked complete or it is in a list called
+// Done, like the GitHub importer does. Labels become tags, named
+// after their color when they have no name, and checklists are added to the
+// description as Markdown task lists.
+func parseTrello(r io.Reader) (ImportedBoard, error) {
+	var export trelloBoard
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return ImportedBoard{}, err
+	}
+	if export.Lists == nil {
+		return ImportedBoard{}, errors.New("no lists found, is this a Trello board export?")
+	}
+
+	// Trello orders everything by pos, not by the order in the file
+	lists, cards, checklists := export.Lists, export.Cards, export.Checklists
+	slices.SortStableFunc(lists, func(a, b trelloList) int { return cmp.Compare(a.Pos, b.Pos) })
+	slices.SortStableFunc(cards, func(a, b trelloCard) int { return cmp.Compare(a.Pos, b.Pos) })
+	slices.SortStableFunc(checklists, func(a, b trelloChecklist) int { return cmp.Compare(a.Pos, b.Pos) })
+
+	board := ImportedBoard{}
+	for _, list := range lists {
+		if list.Closed {
+			continue
+		}
+		section := ImportedSection{Name: list.Name}
+		for _, card := range cards {
+			if card.Closed || card.IDList != list.ID || strings.TrimSpace(card.Name) == "" {
+				continue
+			}
+			note := ImportedNote{
+				Content:     strings.TrimSpace(card.Name),
+				Description: strings.TrimSpace(card.Desc),
+				Checked:     card.DueComplete || strings.EqualFold(list.Name, "done"),
+				Created:     trelloCreated(card.ID),
+			}
+			if card.Due != nil {
+				note.Due = startOfDay(card.Due.Local())
+			}
+			labels := []string{}
+			for _, label := range card.Labels {
+				name := label.Name
+				if name == "" {
+					name = label.Color
+				}
+				labels = append(labels, strings.ReplaceAll(strings.TrimSpace(name), " ", "-"))
+			}
+			note.Tags = parseTags(strings.Join(labels, " "))
+
+			for _, checklist := range checklists {
+				if checklist.IDCard != card.ID {
+					continue
+				}
+				items := "### " + checklist.Name
+				slices.SortStableFunc(checklist.CheckItems, func(a, b trelloCheckItem) int { return cmp.Compare(a.Pos, b.Pos) })
+				for _, item := range checklist.CheckItems {
+					check := " "
+					if item.State == "complete" {
+						check = "x"
+					}
+					items += "\n- [" + check + "] " + item.Name
+				}
+				if note.Description != "" {
+					note.Description += "\n\n"
+				}
+				note.Description += items
+			}
+			section.Notes = append(section.Notes, note)
+		}
+		board.Sections = append(board.Sections, section)
+	}
+	return board, nil
+}
+
+// trelloCreated reads the creation time Trello keeps in the first 8 hex
+// digits of every ID. The zero time when the ID isn't one of those.
+func trelloCreated(id string) time.Time {
+	if len(id) < 8 {
+		return time.Time{}
+	}
+	seconds, err := strconv.ParseInt(id[:8], 16, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(seconds, 0)
+}
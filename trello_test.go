@@ -0,0 +1,61 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTrello(t *testing.T) {
+	created := time.Unix(0x5f000000, 0)
+	due := startOfDay(time.Date(2026, 10, 20, 12, 0, 0, 0, time.UTC).Local())
+
+	tests := []struct {
+		name    string
+		input   string
+		want    ImportedBoard
+		wantErr bool
+	}{
+		{
+			"board",
+			`{
+				"lists": [
+					{"id": "l2", "name": "Done", "pos": 2},
+					{"id": "l1", "name": "To Do", "pos": 1},
+					{"id": "l3", "name": "Old", "closed": true, "pos": 3}
+				],
+				"cards": [
+					{"id": "5f0000000000000000000002", "name": " Second ", "idList": "l1", "pos": 2,
+						"labels": [{"name": "", "color": "red"}, {"name": "Good first issue", "color": "green"}]},
+					{"id": "5f0000000000000000000001", "name": "First", "desc": "Some text", "idList": "l1", "pos": 1,
+						"due": "2026-10-20T12:00:00.000Z", "dueComplete": true},
+					{"id": "c3", "name": "Shipped", "idList": "l2", "pos": 1},
+					{"id": "c4", "name": "Archived", "idList": "l1", "closed": true, "pos": 3},
+					{"id": "c5", "name": "  ", "idList": "l1", "pos": 4},
+					{"id": "c6", "name": "In a closed list", "idList": "l3", "pos": 1}
+				],
+				"checklists": [
+					{"idCard": "5f0000000000000000000001", "name": "Steps", "pos": 1, "checkItems": [
+						{"name": "two", "state": "incomplete", "pos": 2},
+						{"name": "one", "state": "complete", "pos": 1}
+					]}
+				]
+			}`,
+			ImportedBoard{Sections: []ImportedSection{
+				{Name: "To Do", Notes: []ImportedNote{
+					{Content: "First", Description: "Some text\n\n### Steps\n- [x] one\n- [ ] two", Tags: []string{}, Checked: true, Due: due, Created: created},
+					{Content: "Second", Tags: []string{"red", "good-first-issue"}, Created: created},
+				}},
+				{Name: "Done", Notes: []ImportedNote{{Content: "Shipped", Tags: []string{}, Checked: true}}},
+			}},
+			false,
+		},
+		{"no lists", `{"lists": []}`, ImportedBoard{}, false},
+		{"not a Trello export", `{"name": "board"}`, ImportedBoard{}, true},
+		{"not JSON", `lists`, ImportedBoard{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkImport(t, parseTrello, tt.input, tt.want, tt.wantErr)
+		})
+	}
+}
@@ -3,6 +3,11 @@ package main
 import (
 	"crypto/rand"
 	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	lg "github.com/charmbracelet/lipgloss"
 )
 
 func mapSlice(input []int, transform func(int) int) []int {
@@ -24,3 +29,62 @@ func randomHex(n int) string {
 func clamp(minVal, val, maxVal int) int {
 	return max(min(maxVal, val), minVal)
 }
+
+// scrollHint is "▲ 3 more" for things scrolled out of view, empty when there are none
+func scrollHint(arrow string, hidden int) string {
+	if hidden <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s %d more", arrow, hidden)
+}
+
+// truncate cuts s to at most width terminal columns
+func truncate(s string, width int) string {
+	if lg.Width(s) <= width {
+		return s
+	}
+	cut := []rune{}
+	for _, r := range s {
+		if lg.Width(string(cut))+lg.Width(string(r)) > width {
+			break
+		}
+		cut = append(cut, r)
+	}
+	return string(cut)
+}
+
+// writeFileAtomic writes data to a temporary file next to path and renames it
+// over path, so readers see either the old or the new content, never half of it.
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	// No-op once the rename went through
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	// Make the rename itself durable. Not every platform can sync a directory.
+	if dir, err := os.Open(filepath.Dir(path)); err == nil {
+		dir.Sync()
+		dir.Close()
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	lg "github.com/charmbracelet/lipgloss"
+)
+
+const (
+	minCardWidth = 24 // Cards never get narrower, more sections scroll instead
+	sectionGap   = 8  // Blank columns between two sections
+
+	// Lines of the screen that are not cards: the system padding, the board
+	// title, the scroll indicators, section headers and the tallest footer
+	// (the text input with its prompt and the status line).
+	boardChromeHeight = 5 + 2 + 2 + 2 + 2 + 8
+)
+
+// BoardLayout is how much of the board fits on screen
+type BoardLayout struct {
+	CardWidth int
+	Columns   int // Sections shown side by side
+	Rows      int // Cards shown in each section
+}
+
+func (m ProgramModel) Layout() BoardLayout {
+	sectionCount := len(m.UIControl.DisplayOrder)
+	width := m.UIControl.TermSize.Width
+
+	cardWidth := max(minCardWidth, (width-5)/(sectionCount+3))
+	// Width left inside systemStyle's width and padding
+	available := width - 7
+	columns := clamp(1, (available+sectionGap)/(cardWidth+sectionGap), max(sectionCount, 1))
+
+	cardHeight := lg.Height(cardStyle.Width(cardWidth).Render(""))
+	rows := max(1, (m.UIControl.TermSize.Height-boardChromeHeight)/cardHeight)
+
+	return BoardLayout{CardWidth: cardWidth, Columns: columns, Rows: rows}
+}
+
+// FollowCursor scrolls the board so the cursor stays on screen, and keeps
+// every other section's scroll position inside its notes.
+func (m *ProgramModel) FollowCursor() {
+	layout := m.Layout()
+	ui := &m.UIControl
+
+	sectionCount := len(ui.DisplayOrder)
+	if ui.SectionCursor < ui.SectionOffset {
+		ui.SectionOffset = ui.SectionCursor
+	}
+	if ui.SectionCursor >= ui.SectionOffset+layout.Columns {
+		ui.SectionOffset = ui.SectionCursor - layout.Columns + 1
+	}
+	ui.SectionOffset = clamp(0, ui.SectionOffset, max(sectionCount-layout.Columns, 0))
+
+	if ui.RowOffsets == nil {
+		ui.RowOffsets = map[int]int{}
+	}
+	for _, section := range m.SectionData {
+		notes := ui.DisplayOrder[section.ID]
+		offset := ui.RowOffsets[section.ID]
+
+		if section.Order == ui.SectionCursor {
+			if ui.RowCursor < offset {
+				offset = ui.RowCursor
+			}
+			if ui.RowCursor >= offset+layout.Rows {
+				offset = ui.RowCursor - layout.Rows + 1
+			}
+		}
+		ui.RowOffsets[section.ID] = clamp(0, offset, max(len(notes)-layout.Rows, 0))
+	}
+}
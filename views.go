@@ -0,0 +1,125 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Saved views are queries (see query.go) kept in the board file. Cycling
+// through them with v shows only the notes the view's query matches.
+
+// Queries are longer than note titles
+const queryCharLimit = 200
+
+// noteFilter tells which notes make it onto the board with the current tag
+// filter and view
+func (m ProgramModel) noteFilter() func(n *Note) bool {
+	view, hasView := m.ActiveView()
+	query, err := ParseQuery(view.Query, time.Now())
+	if err != nil {
+		// Broken query in a hand edited file, show everything rather than nothing
+		hasView = false
+	}
+	return func(n *Note) bool {
+		if !n.HasTags(m.UIControl.TagFilter) {
+			return false
+		}
+		return !hasView || query.Matches(&m.BoardState, n)
+	}
+}
+
+// ActiveView is the saved view the board is rendered through, if any
+func (m ProgramModel) ActiveView() (SavedView, bool) {
+	if m.UIControl.ViewName == "" {
+		return SavedView{}, false
+	}
+	idx := slices.IndexFunc(m.Views, func(v SavedView) bool { return v.Name == m.UIControl.ViewName })
+	if idx == -1 {
+		return SavedView{}, false
+	}
+	return m.Views[idx], true
+}
+
+// CycleView switches to the next saved view, after the last one back to the whole board
+func (m *ProgramModel) CycleView() {
+	if len(m.Views) == 0 {
+		m.StatusText = "No saved views yet, press V to save one"
+		return
+	}
+
+	next := 0
+	if view, ok := m.ActiveView(); ok {
+		next = slices.IndexFunc(m.Views, func(v SavedView) bool { return v.Name == view.Name }) + 1
+	}
+
+	selected := m.SelectedNote()
+	if next == len(m.Views) {
+		m.UIControl.ViewName = ""
+		m.StatusText = "Showing the whole board"
+	} else {
+		m.UIControl.ViewName = m.Views[next].Name
+		m.StatusText = "View " + m.Views[next].Name + ": " + m.Views[next].Query
+	}
+	m.reselect(selected)
+}
+
+// OpenViewEditor asks for a view as "name: query", starting from the one on screen
+func (m *ProgramModel) OpenViewEditor() tea.Cmd {
+	value := ""
+	if view, ok := m.ActiveView(); ok {
+		value = view.Name + ": " + view.Query
+	}
+	return m.OpenTextInputLimit(SaveViewOperation, "Save a view as name: query (an empty query deletes it)", "e.g. backend: -is:checked tag:backend due:week", value, queryCharLimit)
+}
+
+// SaveView handles the text input of OpenViewEditor
+func (m *ProgramModel) SaveView(value string) {
+	name, query, _ := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	query = strings.TrimSpace(query)
+	if name == "" {
+		m.StatusText = "A view needs a name, e.g. backend: tag:backend"
+		return
+	}
+
+	if query == "" {
+		if m.applyOrReport(DeleteViewCmd{Name: name}) {
+			if m.UIControl.ViewName == name {
+				m.UIControl.ViewName = ""
+			}
+			m.StatusText = "View " + name + " deleted"
+		}
+		return
+	}
+
+	if _, err := ParseQuery(query, time.Now()); err != nil {
+		m.StatusText = "Can't save the view: " + err.Error()
+		return
+	}
+	selected := m.SelectedNote()
+	if m.applyOrReport(SaveViewCmd{Name: name, Query: query}) {
+		m.UIControl.ViewName = name
+		m.reselect(selected)
+	}
+}
+
+// reselect keeps the cursor on selected after the notes on screen changed,
+// or moves it to the top when selected is gone
+func (m *ProgramModel) reselect(selected *Note) {
+	m.RepopulateDisplayOrder()
+	if selected == nil || !m.SelectNote(selected.ID) {
+		m.UIControl.RowCursor = 0
+	}
+}
+
+// viewTitle is shown next to the board title while a view is on
+func (m ProgramModel) viewTitle() string {
+	view, ok := m.ActiveView()
+	if !ok {
+		return ""
+	}
+	return "  " + scrollHintStyle.Render("view:") + " " + boardPickerCursorStyle.Render(view.Name)
+}